@@ -0,0 +1,107 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package audits
+
+import (
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"strings"
+)
+
+/* Consistency Check */
+
+// Discrepancy is a rule or block whose local evaluation disagrees with the
+// status DegreeWorks reported for it.
+type Discrepancy struct {
+	Block           string  `json:"block"`
+	Rule            string  `json:"rule,omitempty"`
+	Local           bool    `json:"local"`
+	Official        bool    `json:"official"`
+	PercentComplete float64 `json:"percentComplete"`
+	InProgress      bool    `json:"inProgress"`
+}
+
+func (discrepancy Discrepancy) String() string {
+	label := discrepancy.Block
+	if len(discrepancy.Rule) > 0 {
+		label += ": " + discrepancy.Rule
+	}
+	verdict := func(completed bool) string {
+		if completed {
+			return "complete"
+		}
+		return "incomplete"
+	}
+	official := fmt.Sprintf("%v (%v%%)", verdict(discrepancy.Official), discrepancy.PercentComplete)
+	if discrepancy.InProgress {
+		official += ", in progress"
+	}
+	return fmt.Sprintf("%v is %v locally, but DegreeWorks reports it %v", label, verdict(discrepancy.Local), official)
+}
+
+// ConsistencyReport separates genuine disagreements from those over rules and
+// blocks still in progress, which count in-progress classes locally but which
+// DegreeWorks doesn't report complete until their grades are posted.
+type ConsistencyReport struct {
+	Disagreements []Discrepancy `json:"disagreements"`
+	Pending       []Discrepancy `json:"pending"`
+}
+
+func (report *ConsistencyReport) add(discrepancy Discrepancy) {
+	if discrepancy.InProgress && discrepancy.Local && !discrepancy.Official {
+		report.Pending = append(report.Pending, discrepancy)
+	} else {
+		report.Disagreements = append(report.Disagreements, discrepancy)
+	}
+}
+
+// CheckConsistency compares local IsCompleted with DegreeWorks's completion
+// flags for every block and every rule in their trees. Rules the audit
+// reported no status for are skipped.
+func CheckConsistency(student *types.Student) ConsistencyReport {
+	report := ConsistencyReport{Disagreements: make([]Discrepancy, 0), Pending: make([]Discrepancy, 0)}
+	for _, block := range student.Blocks {
+		if block.Status.Reported {
+			if local := block.IsCompleted(student); local != block.Status.IsSatisfied() {
+				report.add(newDiscrepancy(block.Title, "", local, block.Status))
+			}
+		}
+		for _, rule := range block.Rules {
+			checkRule(student, block.Title, nil, rule, &report)
+		}
+	}
+	return report
+}
+
+func checkRule(student *types.Student, block string, path []string, rule types.Rule, report *ConsistencyReport) {
+	path = append(path, rule.Label)
+	if rule.Status.Reported {
+		if local := rule.IsCompleted(student); local != rule.Status.IsSatisfied() {
+			report.add(newDiscrepancy(block, strings.Join(path, " > "), local, rule.Status))
+		}
+	}
+	for _, child := range rule.Rules {
+		checkRule(student, block, path, child, report)
+	}
+}
+
+func newDiscrepancy(block string, rule string, local bool, status types.Status) Discrepancy {
+	return Discrepancy{Block: block, Rule: rule, Local: local, Official: status.IsSatisfied(), PercentComplete: status.PercentComplete, InProgress: status.InProgress}
+}
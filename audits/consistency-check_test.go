@@ -0,0 +1,87 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package audits
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"reflect"
+	"testing"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	done := types.Status{Reported: true, PercentComplete: 100.0}
+	half := types.Status{Reported: true, PercentComplete: 50.0}
+	inProgress := types.Status{Reported: true, PercentComplete: 50.0, InProgress: true}
+	completed := courseRule("Algorithms", 1, []string{"COMPSCI161"})
+	completed.Requirements[0].Completed = []string{"COMPSCI161"}
+	pending := courseRule("Algorithms", 1, []string{"COMPSCI161"})
+	tests := []struct {
+		name          string
+		rule          types.Rule
+		status        types.Status
+		disagreements []string
+		pending       []string
+	}{
+		{"agrees complete", completed, done, []string{}, []string{}},
+		{"agrees incomplete", pending, half, []string{}, []string{}},
+		{"not reported", completed, types.Status{}, []string{}, []string{}},
+		{"complete locally only", completed, half, []string{"Major: Algorithms"}, []string{}},
+		{"complete officially only", pending, done, []string{"Major: Algorithms"}, []string{}},
+		{"in progress", completed, inProgress, []string{}, []string{"Major: Algorithms"}},
+	}
+	for _, test := range tests {
+		rule := test.rule
+		rule.Status = test.status
+		student := takenStudent([]string{"COMPSCI161"}, []types.Block{{Title: "Major", Rules: []types.Rule{rule}}})
+		report := CheckConsistency(&student)
+		if labels := discrepancyLabels(report.Disagreements); !reflect.DeepEqual(labels, test.disagreements) {
+			t.Errorf("%v: disagreements = %v, want %v", test.name, labels, test.disagreements)
+		}
+		if labels := discrepancyLabels(report.Pending); !reflect.DeepEqual(labels, test.pending) {
+			t.Errorf("%v: pending = %v, want %v", test.name, labels, test.pending)
+		}
+	}
+}
+
+func TestCheckConsistencyNestedRules(t *testing.T) {
+	child := courseRule("Algorithms", 1, []string{"COMPSCI161"})
+	child.Requirements[0].Completed = []string{"COMPSCI161"}
+	child.Status = types.Status{Reported: true, PercentComplete: 0.0}
+	group := types.Rule{Label: "Core", Kind: types.GroupRule, Required: 1, Rules: []types.Rule{child}}
+	block := types.Block{Title: "Major", Rules: []types.Rule{group}, Status: types.Status{Reported: true, PercentComplete: 100.0}}
+	student := takenStudent([]string{"COMPSCI161"}, []types.Block{block})
+	report := CheckConsistency(&student)
+	expected := []string{"Major: Core > Algorithms"}
+	if labels := discrepancyLabels(report.Disagreements); !reflect.DeepEqual(labels, expected) {
+		t.Errorf("disagreements = %v, want %v", labels, expected)
+	}
+}
+
+func discrepancyLabels(discrepancies []Discrepancy) []string {
+	labels := make([]string, 0)
+	for _, discrepancy := range discrepancies {
+		label := discrepancy.Block
+		if len(discrepancy.Rule) > 0 {
+			label += ": " + discrepancy.Rule
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
@@ -0,0 +1,84 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package audits
+
+/* Max Flow (Edmonds-Karp) */
+
+type edge struct {
+	to       int
+	capacity int
+	flow     int
+	reverse  int
+}
+
+type flow struct {
+	graph [][]edge
+}
+
+func newFlow(n int) *flow {
+	return &flow{graph: make([][]edge, n)}
+}
+
+func (f *flow) addEdge(from int, to int, capacity int) {
+	f.graph[from] = append(f.graph[from], edge{to: to, capacity: capacity, reverse: len(f.graph[to])})
+	f.graph[to] = append(f.graph[to], edge{to: from, capacity: 0, reverse: len(f.graph[from]) - 1})
+}
+
+func (f *flow) maxFlow(source int, sink int) int {
+	total := 0
+	for {
+		type step struct {
+			node int
+			edge int
+		}
+		previous := make([]step, len(f.graph))
+		for i := range previous {
+			previous[i] = step{node: -1}
+		}
+		previous[source] = step{node: source}
+		queue := []int{source}
+		for (len(queue) > 0) && (previous[sink].node < 0) {
+			u := queue[0]
+			queue = queue[1:]
+			for i, e := range f.graph[u] {
+				if (previous[e.to].node < 0) && (e.capacity - e.flow > 0) {
+					previous[e.to] = step{node: u, edge: i}
+					queue = append(queue, e.to)
+				}
+			}
+		}
+		if previous[sink].node < 0 {
+			return total
+		}
+		bottleneck := -1
+		for v := sink; v != source; v = previous[v].node {
+			e := f.graph[previous[v].node][previous[v].edge]
+			if (bottleneck < 0) || (e.capacity - e.flow < bottleneck) {
+				bottleneck = e.capacity - e.flow
+			}
+		}
+		for v := sink; v != source; v = previous[v].node {
+			e := &f.graph[previous[v].node][previous[v].edge]
+			e.flow += bottleneck
+			f.graph[v][e.reverse].flow -= bottleneck
+		}
+		total += bottleneck
+	}
+}
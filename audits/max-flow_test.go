@@ -0,0 +1,51 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package audits
+
+import (
+	"testing"
+)
+
+func TestMaxFlow(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodes    int
+		edges    [][3]int
+		expected int
+	}{
+		{"no edges", 2, [][3]int{}, 0},
+		{"single edge", 2, [][3]int{{0, 1, 3}}, 3},
+		{"bottleneck", 3, [][3]int{{0, 2, 5}, {2, 1, 2}}, 2},
+		{"parallel paths", 4, [][3]int{{0, 2, 1}, {0, 3, 1}, {2, 1, 1}, {3, 1, 1}}, 2},
+		// Needs the reverse edge of 2→3 to reroute the first augmenting path.
+		{"rerouting", 6, [][3]int{{0, 2, 1}, {0, 3, 1}, {2, 4, 1}, {2, 5, 1}, {3, 4, 1}, {4, 1, 1}, {5, 1, 1}}, 2},
+		{"bipartite matching", 8, [][3]int{{0, 2, 1}, {0, 3, 1}, {0, 4, 1}, {2, 5, 1}, {3, 5, 1}, {4, 6, 1}, {4, 7, 1}, {5, 1, 1}, {6, 1, 1}, {7, 1, 1}}, 2},
+	}
+	for _, test := range tests {
+		f := newFlow(test.nodes)
+		for _, e := range test.edges {
+			f.addEdge(e[0], e[1], e[2])
+		}
+		if total := f.maxFlow(0, 1); total != test.expected {
+			t.Errorf("%v: maxFlow = %d, want %d", test.name, total, test.expected)
+		}
+	}
+}
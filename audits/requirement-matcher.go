@@ -0,0 +1,371 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package audits
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"sort"
+)
+
+/* Requirement Matcher */
+
+// maxCombinations caps how many ways of completing a single rule are tried.
+// Reports note when a rule had more.
+const maxCombinations = 256
+
+// maxAssignments caps how many assignments the search for the best set of
+// rules checks in all. Reports note when it ran out.
+const maxAssignments = 4096
+
+const unitsPerClass = 4.0
+
+type Assignment struct {
+	Block       string   `json:"block"`
+	Rule        string   `json:"rule"`
+	Requirement int      `json:"requirement"`
+	Courses     []string `json:"courses"`
+}
+
+type AssignmentReport struct {
+	CompletedRules      int          `json:"completedRules"`
+	AuditCompletedRules int          `json:"auditCompletedRules"`
+	Better              bool         `json:"better"`
+	Truncated           bool         `json:"truncated"`
+	Assignments         []Assignment `json:"assignments"`
+}
+
+// Restrictions lists pairs of block titles that may not count the same course.
+type Restrictions map[[2]string]bool
+
+func (restrictions Restrictions) Forbids(blockA string, blockB string) bool {
+	return restrictions[[2]string{blockA, blockB}] || restrictions[[2]string{blockB, blockA}]
+}
+
+// SharingRestrictions derives restrictions from the audit's NonExclusive and
+// ShareWith qualifiers. Audits that use neither let every block share.
+// Otherwise blocks are exclusive unless one of them allows the other.
+func SharingRestrictions(student *types.Student) Restrictions {
+	restrictions := Restrictions{}
+	qualified := false
+	for _, block := range student.Blocks {
+		if block.Qualifiers.NonExclusive || (len(block.Qualifiers.ShareWith) > 0) {
+			qualified = true
+			break
+		}
+	}
+	if !qualified {
+		return restrictions
+	}
+	for i, a := range student.Blocks {
+		for _, b := range student.Blocks[i+1:] {
+			if (a.Title != b.Title) && !a.SharesWith(b) {
+				restrictions[[2]string{a.Title, b.Title}] = true
+			}
+		}
+	}
+	return restrictions
+}
+
+type reqNode struct {
+	block    int
+	rule     int
+	index    int
+	required int
+	eligible []string
+}
+
+type candidateRule struct {
+	plans [][]int
+}
+
+type matcher struct {
+	student    *types.Student
+	reqs       []reqNode
+	rules      []candidateRule
+	courses    []string
+	components []int
+	truncated  bool
+}
+
+// components groups blocks that are linked by a restriction. A course is used
+// at most once within each group; restrictions are treated as transitive.
+func components(blocks []types.Block, restrictions Restrictions) []int {
+	parent := make([]int, len(blocks))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range blocks {
+		for j := i + 1; j < len(blocks); j++ {
+			if restrictions.Forbids(blocks[i].Title, blocks[j].Title) {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+	result := make([]int, len(blocks))
+	for i := range blocks {
+		result[i] = find(i)
+	}
+	return result
+}
+
+// combinations visits the k-element subsets of 0..n-1 in order, stopping
+// once visit returns false. It reports whether every subset was visited.
+func combinations(n int, k int, visit func(combination []int) bool) bool {
+	combination := make([]int, 0, k)
+	var build func(start int) bool
+	build = func(start int) bool {
+		if len(combination) == k {
+			c := make([]int, k)
+			copy(c, combination)
+			return visit(c)
+		}
+		for i := start; i < n; i++ {
+			combination = append(combination, i)
+			if !build(i + 1) {
+				return false
+			}
+			combination = combination[:len(combination)-1]
+		}
+		return true
+	}
+	return build(0)
+}
+
+func newMatcher(student *types.Student, restrictions Restrictions) *matcher {
+	m := &matcher{student: student, reqs: make([]reqNode, 0), rules: make([]candidateRule, 0), courses: make([]string, 0)}
+	m.components = components(student.Blocks, restrictions)
+	for key := range student.Courses {
+		if student.Taken[key] {
+			m.courses = append(m.courses, key)
+		}
+	}
+	sort.Strings(m.courses)
+	
+	for b, block := range student.Blocks {
+		for r, rule := range block.Flatten(student) {
+			if !matchable(rule) {
+				continue
+			}
+			viable := make([]int, 0)
+			for q, req := range rule.Requirements {
+				eligible := make([]string, 0)
+				for _, key := range m.courses {
+					if ok, _ := rule.Qualifiers.Admits(student.Courses[key]); !ok {
+						continue
+					}
+					if req.Accepts(student.Courses[key]) || contains(req.Completed, key) {
+						eligible = append(eligible, key)
+					}
+				}
+				required := classesRequired(req)
+				if len(eligible) >= required {
+					viable = append(viable, len(m.reqs))
+					m.reqs = append(m.reqs, reqNode{block: b, rule: r, index: q, required: required, eligible: eligible})
+				}
+			}
+			if len(viable) < rule.Required {
+				continue
+			}
+			// Plans that can't be filled even on their own are dropped, so every
+			// rule left in the search could still be completed.
+			plans := make([][]int, 0)
+			tried := 0
+			complete := combinations(len(viable), rule.Required, func(combination []int) bool {
+				if tried == maxCombinations {
+					return false
+				}
+				tried++
+				plan := make([]int, len(combination))
+				for i, c := range combination {
+					plan[i] = viable[c]
+				}
+				if _, ok := m.assign(plan); ok {
+					plans = append(plans, plan)
+				}
+				return true
+			})
+			if !complete {
+				m.truncated = true
+			}
+			if len(plans) > 0 {
+				m.rules = append(m.rules, candidateRule{plans: plans})
+			}
+		}
+	}
+	return m
+}
+
+// matchable reports whether the rule is completed by courses at all, and so
+// whether the matcher can do anything for it. Noncourse rules and rules with
+// nothing left to require are left out, from the audit's count as well.
+func matchable(rule types.Rule) bool {
+	return (rule.Kind != types.NoncourseRule) && (rule.Required > 0) && (len(rule.Requirements) > 0)
+}
+
+// classesRequired counts unit thresholds as classes of unitsPerClass units,
+// since the matcher assigns whole classes.
+func classesRequired(req types.Requirement) int {
+	if req.Credits == 0.0 {
+		return req.Required
+	}
+	classes := req.Remaining(unitsPerClass) + len(req.Completed)
+	if classes < req.Required {
+		return req.Required
+	}
+	return classes
+}
+
+func contains(slice []string, s string) bool {
+	for _, e := range slice {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// assign checks whether every targeted requirement can be filled at once,
+// using max flow from courses (one unit per course and block group) to
+// requirements (one unit per required class). It returns the courses
+// assigned to each requirement when it can.
+func (m *matcher) assign(targets []int) (map[int][]string, bool) {
+	type courseGroup struct {
+		course    string
+		component int
+	}
+	nodes := make(map[courseGroup]int, 0)
+	n := 2
+	for _, t := range targets {
+		for _, key := range m.reqs[t].eligible {
+			cg := courseGroup{course: key, component: m.components[m.reqs[t].block]}
+			if _, ok := nodes[cg]; !ok {
+				nodes[cg] = n
+				n++
+			}
+		}
+	}
+	reqBase := n
+	n += len(targets)
+	
+	f := newFlow(n)
+	demand := 0
+	for _, id := range nodes {
+		f.addEdge(0, id, 1)
+	}
+	for i, t := range targets {
+		req := m.reqs[t]
+		demand += req.required
+		f.addEdge(reqBase+i, 1, req.required)
+		for _, key := range req.eligible {
+			f.addEdge(nodes[courseGroup{course: key, component: m.components[req.block]}], reqBase+i, 1)
+		}
+	}
+	if f.maxFlow(0, 1) < demand {
+		return nil, false
+	}
+	
+	assigned := make(map[int][]string, 0)
+	for cg, id := range nodes {
+		for _, e := range f.graph[id] {
+			if (e.to >= reqBase) && (e.to < reqBase+len(targets)) && (e.flow > 0) {
+				t := targets[e.to-reqBase]
+				assigned[t] = append(assigned[t], cg.course)
+			}
+		}
+	}
+	return assigned, true
+}
+
+// MatchRequirements finds an assignment of completed courses to requirements
+// that completes as many rules as possible, and compares it with the number
+// of rules the audit's own placement completes.
+func MatchRequirements(student *types.Student, restrictions Restrictions) AssignmentReport {
+	m := newMatcher(student, restrictions)
+	report := AssignmentReport{Assignments: make([]Assignment, 0)}
+	for _, block := range student.Blocks {
+		for _, rule := range block.Flatten(student) {
+			if matchable(rule) && rule.IsCompleted(student) {
+				report.AuditCompletedRules++
+			}
+		}
+	}
+	
+	// Every remaining rule has a plan that fits on its own, so completing all
+	// of them bounds what a branch can reach. Once maxAssignments have been
+	// checked, the remaining rules are left incomplete.
+	best := -1
+	checked := 0
+	var bestAssigned map[int][]string
+	targets := make([]int, 0)
+	var search func(i int, completed int, assigned map[int][]string)
+	search = func(i int, completed int, assigned map[int][]string) {
+		if completed + (len(m.rules) - i) <= best {
+			return
+		}
+		if i == len(m.rules) {
+			best = completed
+			bestAssigned = assigned
+			return
+		}
+		for _, plan := range m.rules[i].plans {
+			if checked == maxAssignments {
+				m.truncated = true
+				break
+			}
+			checked++
+			n := len(targets)
+			targets = append(targets, plan...)
+			if next, ok := m.assign(targets); ok {
+				search(i+1, completed+1, next)
+			}
+			targets = targets[:n]
+		}
+		search(i+1, completed, assigned)
+	}
+	search(0, 0, map[int][]string{})
+	
+	report.CompletedRules = best
+	report.Better = best > report.AuditCompletedRules
+	report.Truncated = m.truncated
+	for t, courses := range bestAssigned {
+		req := m.reqs[t]
+		block := student.Blocks[req.block]
+		sort.Strings(courses)
+		report.Assignments = append(report.Assignments, Assignment{Block: block.Title, Rule: block.Flatten(student)[req.rule].Label, Requirement: req.index, Courses: courses})
+	}
+	sort.Slice(report.Assignments, func(i, j int) bool {
+		a, b := report.Assignments[i], report.Assignments[j]
+		if a.Block != b.Block {
+			return a.Block < b.Block
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Requirement < b.Requirement
+	})
+	return report
+}
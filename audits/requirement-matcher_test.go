@@ -0,0 +1,160 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package audits
+
+import (
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"testing"
+)
+
+func courseRule(label string, required int, options ...[]string) types.Rule {
+	rule := types.Rule{Label: label, Kind: types.CourseRule, Required: required, Requirements: make([]types.Requirement, 0)}
+	for _, o := range options {
+		rule.Requirements = append(rule.Requirements, types.Requirement{Required: 1, Options: o})
+	}
+	return rule
+}
+
+func takenStudent(keys []string, blocks []types.Block) types.Student {
+	student := types.Student{Courses: map[string]types.Course{}, Taken: map[string]bool{}, Blocks: blocks}
+	for _, key := range keys {
+		student.Courses[key] = types.Course{Department: key[:len(key)-3], Number: key[len(key)-3:], Units: 4.0, Grade: "A"}
+		student.Taken[key] = true
+	}
+	return student
+}
+
+func TestMatchRequirements(t *testing.T) {
+	tests := []struct {
+		name      string
+		taken     []string
+		blocks    []types.Block
+		completed int
+	}{
+		{"one course, one rule", []string{"COMPSCI161"}, []types.Block{{Title: "Major", Rules: []types.Rule{
+			courseRule("Algorithms", 1, []string{"COMPSCI161"}),
+		}}}, 1},
+		{"course counted once within a block", []string{"COMPSCI161"}, []types.Block{{Title: "Major", Rules: []types.Rule{
+			courseRule("Algorithms", 1, []string{"COMPSCI161"}),
+			courseRule("Elective", 1, []string{"COMPSCI161"}),
+		}}}, 1},
+		{"swap to complete both", []string{"COMPSCI161", "COMPSCI171"}, []types.Block{{Title: "Major", Rules: []types.Rule{
+			courseRule("Elective", 1, []string{"COMPSCI161", "COMPSCI171"}),
+			courseRule("Algorithms", 1, []string{"COMPSCI161"}),
+		}}}, 2},
+		{"one of two requirements", []string{"COMPSCI171"}, []types.Block{{Title: "Major", Rules: []types.Rule{
+			courseRule("Either", 1, []string{"COMPSCI161"}, []string{"COMPSCI171"}),
+		}}}, 1},
+		{"blocks share without restrictions", []string{"COMPSCI161"}, []types.Block{
+			{Title: "Major", Rules: []types.Rule{courseRule("Algorithms", 1, []string{"COMPSCI161"})}},
+			{Title: "Minor", Rules: []types.Rule{courseRule("Algorithms", 1, []string{"COMPSCI161"})}},
+		}, 2},
+	}
+	for _, test := range tests {
+		student := takenStudent(test.taken, test.blocks)
+		report := MatchRequirements(&student, SharingRestrictions(&student))
+		if report.CompletedRules != test.completed {
+			t.Errorf("%v: CompletedRules = %d, want %d", test.name, report.CompletedRules, test.completed)
+		}
+		if report.Truncated {
+			t.Errorf("%v: unexpectedly truncated", test.name)
+		}
+	}
+}
+
+func TestMatchRequirementsReportsTruncation(t *testing.T) {
+	options := make([][]string, 0)
+	keys := make([]string, 0)
+	for i := 100; i < 112; i++ {
+		key := fmt.Sprintf("COMPSCI%d", i)
+		keys = append(keys, key)
+		options = append(options, []string{key})
+	}
+	// Choosing 6 of 12 requirements has 924 combinations.
+	student := takenStudent(keys, []types.Block{{Title: "Major", Rules: []types.Rule{courseRule("Electives", 6, options...)}}})
+	report := MatchRequirements(&student, SharingRestrictions(&student))
+	if !report.Truncated {
+		t.Errorf("MatchRequirements over %d combinations wasn't reported as truncated", 924)
+	}
+	if report.CompletedRules != 1 {
+		t.Errorf("CompletedRules = %d, want 1", report.CompletedRules)
+	}
+}
+
+func TestMatchRequirementsComparesCourseRules(t *testing.T) {
+	algorithms := courseRule("Algorithms", 1, []string{"COMPSCI161"})
+	algorithms.Requirements[0].Completed = []string{"COMPSCI161"}
+	student := takenStudent([]string{"COMPSCI161"}, []types.Block{{Title: "Major", Rules: []types.Rule{
+		algorithms,
+		{Label: "Residency", Kind: types.NoncourseRule, Required: 1, Requirements: []types.Requirement{}, Status: types.Status{Reported: true, PercentComplete: 100.0}},
+		{Label: "Writing", Kind: types.NoncourseRule, Required: 1, Requirements: []types.Requirement{}, Status: types.Status{Reported: true}},
+	}}})
+	report := MatchRequirements(&student, SharingRestrictions(&student))
+	if (report.CompletedRules != 1) || (report.AuditCompletedRules != 1) || report.Better {
+		t.Errorf("CompletedRules = %d, AuditCompletedRules = %d, Better = %v, want 1, 1, false", report.CompletedRules, report.AuditCompletedRules, report.Better)
+	}
+}
+
+func TestMatchRequirementsBudget(t *testing.T) {
+	keys := []string{"COMPSCI161", "COMPSCI162", "COMPSCI163"}
+	rules := make([]types.Rule, 0)
+	for i := 0; i < 16; i++ {
+		rules = append(rules, courseRule(fmt.Sprintf("Rule %d", i), 1, []string{keys[0]}, []string{keys[1]}, []string{keys[2]}))
+	}
+	// Only three of the rules can be completed, so the bound barely prunes.
+	student := takenStudent(keys, []types.Block{{Title: "Major", Rules: rules}})
+	report := MatchRequirements(&student, SharingRestrictions(&student))
+	if !report.Truncated {
+		t.Errorf("MatchRequirements over %d rules wasn't reported as truncated", len(rules))
+	}
+	if report.CompletedRules != 3 {
+		t.Errorf("CompletedRules = %d, want 3", report.CompletedRules)
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	tests := []struct {
+		n        int
+		k        int
+		limit    int
+		count    int
+		complete bool
+	}{
+		{4, 2, 100, 6, true},
+		{5, 0, 100, 1, true},
+		{3, 4, 100, 0, true},
+		{10, 3, 5, 5, false},
+	}
+	for _, test := range tests {
+		count := 0
+		complete := combinations(test.n, test.k, func(combination []int) bool {
+			if count == test.limit {
+				return false
+			}
+			count++
+			return true
+		})
+		if (count != test.count) || (complete != test.complete) {
+			t.Errorf("combinations(%d, %d) visited %d (complete %v), want %d (complete %v)", test.n, test.k, count, complete, test.count, test.complete)
+		}
+	}
+}
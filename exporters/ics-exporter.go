@@ -0,0 +1,224 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package exporters
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"strings"
+	"time"
+)
+
+/* iCalendar Exporter */
+
+const ICSProductID = "-//PeterPlanner//PeterPlanner//EN"
+const ICSTimeZone = "America/Los_Angeles"
+
+const icsLocalFormat = "20060102T150405"
+const icsUTCFormat = "20060102T150405Z"
+
+const icsTimeZoneDefinition = `BEGIN:VTIMEZONE
+TZID:America/Los_Angeles
+X-LIC-LOCATION:America/Los_Angeles
+BEGIN:DAYLIGHT
+TZOFFSETFROM:-0800
+TZOFFSETTO:-0700
+TZNAME:PDT
+DTSTART:19700308T020000
+RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU
+END:DAYLIGHT
+BEGIN:STANDARD
+TZOFFSETFROM:-0700
+TZOFFSETTO:-0800
+TZNAME:PST
+DTSTART:19701101T020000
+RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU
+END:STANDARD
+END:VTIMEZONE`
+
+var icsWeekdays = map[time.Weekday]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+type section struct {
+	course types.Course
+	class  types.Class
+}
+
+func findSections(courses map[string]types.Course, yearTerm string, codes []string) ([]section, error) {
+	sections := make([]section, 0)
+	for _, code := range codes {
+		code = strings.TrimSpace(code)
+		found := false
+		for _, course := range courses {
+			for _, class := range course.Classes[yearTerm] {
+				if strings.TrimSpace(class.Code) == code {
+					sections = append(sections, section{course: course, class: class})
+					found = true
+					break
+				}
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New(fmt.Sprintf("ERROR: Unable to find class with code `%v` in term %v.", code, yearTerm))
+		}
+	}
+	return sections, nil
+}
+
+func ExportICS(courses map[string]types.Course, yearTerm string, codes []string, calendar *types.Calendar, clock types.Clock) (string, error) {
+	term, ok := (*calendar).TermDates(yearTerm)
+	if !ok {
+		return "", errors.New(fmt.Sprintf("ERROR: Unable to export iCalendar file. No calendar dates for term %v.", yearTerm))
+	}
+	sections, err := findSections(courses, yearTerm, codes)
+	if err != nil {
+		return "", err
+	}
+	
+	stamp := clock.Now().UTC().Format(icsUTCFormat)
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + ICSProductID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+	lines = append(lines, strings.Split(icsTimeZoneDefinition, "\n")...)
+	for _, s := range sections {
+		lines = append(lines, weeklyEvent(s, yearTerm, term, calendar, stamp)...)
+		lines = append(lines, finalEvent(s, yearTerm, term, stamp)...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+	
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldLine(line))
+		b.WriteString("\r\n")
+	}
+	return b.String(), nil
+}
+
+func weeklyEvent(s section, yearTerm string, term types.TermDates, calendar *types.Calendar, stamp string) []string {
+	class := s.class
+	if (len(class.Days) == 0) || class.Time.IsZero() {
+		return nil
+	}
+	
+	first := term.InstructionStart.Time
+	for !class.MeetsOn(first.Weekday()) {
+		first = first.AddDate(0, 0, 1)
+	}
+	if !term.IsInstruction(first) {
+		return nil
+	}
+	meeting := class.Time.On(first)
+	
+	byDay := make([]string, 0)
+	for _, day := range class.Days {
+		byDay = append(byDay, icsWeekdays[day])
+	}
+	until := term.InstructionEnd.AddDate(0, 0, 1).Add(-time.Second).UTC()
+	
+	lines := []string{
+		"BEGIN:VEVENT",
+		fmt.Sprintf("UID:%v-%v@peterplanner.com", yearTerm, strings.TrimSpace(class.Code)),
+		"DTSTAMP:" + stamp,
+		fmt.Sprintf("DTSTART;TZID=%v:%v", ICSTimeZone, meeting.Start.Format(icsLocalFormat)),
+		fmt.Sprintf("DTEND;TZID=%v:%v", ICSTimeZone, meeting.End.Format(icsLocalFormat)),
+		fmt.Sprintf("RRULE:FREQ=WEEKLY;BYDAY=%v;UNTIL=%v", strings.Join(byDay, ","), until.Format(icsUTCFormat)),
+	}
+	for _, holiday := range (*calendar).Holidays {
+		if term.IsInstruction(holiday.Date.Time) && class.MeetsOn(holiday.Date.Weekday()) {
+			excluded := class.Time.On(holiday.Date.Time)
+			lines = append(lines, fmt.Sprintf("EXDATE;TZID=%v:%v", ICSTimeZone, excluded.Start.Format(icsLocalFormat)))
+		}
+	}
+	lines = append(lines,
+		"SUMMARY:" + escapeText(fmt.Sprintf("%v %v %v %v", s.course.Department, s.course.Number, strings.TrimSpace(class.Type), class.Section)),
+		"LOCATION:" + escapeText(class.Place),
+		"DESCRIPTION:" + escapeText(description(s)),
+		"END:VEVENT",
+	)
+	return lines
+}
+
+func finalEvent(s section, yearTerm string, term types.TermDates, stamp string) []string {
+	final, ok := types.ParseFinal(s.class.Final, term)
+	if !ok {
+		return nil
+	}
+	return []string{
+		"BEGIN:VEVENT",
+		fmt.Sprintf("UID:%v-%v-final@peterplanner.com", yearTerm, strings.TrimSpace(s.class.Code)),
+		"DTSTAMP:" + stamp,
+		fmt.Sprintf("DTSTART;TZID=%v:%v", ICSTimeZone, final.Start.Format(icsLocalFormat)),
+		fmt.Sprintf("DTEND;TZID=%v:%v", ICSTimeZone, final.End.Format(icsLocalFormat)),
+		"SUMMARY:" + escapeText(fmt.Sprintf("Final: %v %v", s.course.Department, s.course.Number)),
+		"DESCRIPTION:" + escapeText(description(s)),
+		"END:VEVENT",
+	}
+}
+
+func description(s section) string {
+	title := s.course.Title
+	if len(title) == 0 {
+		title = s.course.ShortTitle
+	}
+	return fmt.Sprintf("%v\nCode: %v\nInstructor: %v", title, strings.TrimSpace(s.class.Code), s.class.Instructor)
+}
+
+func escapeText(text string) string {
+	text = strings.Replace(text, "\\", "\\\\", -1)
+	text = strings.Replace(text, ";", "\\;", -1)
+	text = strings.Replace(text, ",", "\\,", -1)
+	text = strings.Replace(text, "\n", "\\n", -1)
+	return text
+}
+
+// foldLine splits content lines longer than 75 octets (RFC 5545, section 3.1)
+// without breaking apart multi-byte characters.
+func foldLine(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width + size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,168 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package exporters
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFoldLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		lines []string
+	}{
+		{"short", "SUMMARY:COMPSCI 161", []string{"SUMMARY:COMPSCI 161"}},
+		{"exactly 75 octets", strings.Repeat("a", 75), []string{strings.Repeat("a", 75)}},
+		{"76 octets", strings.Repeat("a", 76), []string{strings.Repeat("a", 75), " a"}},
+		{"continuations hold 74 octets", strings.Repeat("a", 75+74+1), []string{strings.Repeat("a", 75), " " + strings.Repeat("a", 74), " a"}},
+		{"multi-byte character at the boundary", strings.Repeat("a", 74) + "é", []string{strings.Repeat("a", 74), " é"}},
+	}
+	for _, test := range tests {
+		if folded := foldLine(test.line); folded != strings.Join(test.lines, "\r\n") {
+			t.Errorf("%v: foldLine = %q, want %q", test.name, folded, strings.Join(test.lines, "\r\n"))
+		}
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"DBH 1100", "DBH 1100"},
+		{"SHINDLER, M.; GOODRICH, M.", `SHINDLER\, M.\; GOODRICH\, M.`},
+		{"Algorithms\nCode: 34000", "Algorithms\\nCode: 34000"},
+		{"C:\\", "C:\\\\"},
+	}
+	for _, test := range tests {
+		if escaped := escapeText(test.text); escaped != test.expected {
+			t.Errorf("escapeText(%q) = %q, want %q", test.text, escaped, test.expected)
+		}
+	}
+}
+
+func testCalendar() types.Calendar {
+	return types.Calendar{
+		Terms: map[string]types.TermDates{"2017-92": {
+			InstructionStart: types.NewDate(2017, time.September, 28),
+			InstructionEnd:   types.NewDate(2017, time.December, 8),
+			FinalsStart:      types.NewDate(2017, time.December, 9),
+			FinalsEnd:        types.NewDate(2017, time.December, 15),
+		}},
+		Holidays: []types.Holiday{
+			{Date: types.NewDate(2017, time.November, 10), Name: "Veterans Day"},
+			{Date: types.NewDate(2017, time.November, 23), Name: "Thanksgiving"},
+			{Date: types.NewDate(2017, time.November, 24), Name: "Thanksgiving"},
+		},
+	}
+}
+
+func TestExportICS(t *testing.T) {
+	course := types.Course{Department: "COMPSCI", Number: "161", Title: "Design and Analysis of Algorithms, with an unusually long title to force folding", Classes: map[string][]types.Class{"2017-92": {
+		{Code: "34000", Type: "Lec", Section: "A", Days: types.ParseDays("MWF"), Time: types.ParseTime(" 9:00- 9:50"), Place: "SSLH 100", Final: "Mon, Dec 11, 8:00-10:00am", Instructor: "SHINDLER, M."},
+		{Code: "34010", Type: "Dis", Section: "1", Days: types.ParseDays("TuTh"), Time: types.ParseTime(" 2:00- 2:50p"), Place: "ICS 174", Instructor: "STAFF"},
+		{Code: "34020", Type: "Lab", Section: "2", Place: "TBA", Instructor: "STAFF"},
+	}}}
+	courses := map[string]types.Course{"COMPSCI161": course}
+	calendar := testCalendar()
+	clock := types.FixedClock{Time: time.Date(2017, time.September, 1, 12, 0, 0, 0, time.UTC)}
+	
+	tests := []struct {
+		name     string
+		code     string
+		contains []string
+		excludes []string
+	}{
+		{"lecture", "34000", []string{
+			"DTSTAMP:20170901T120000Z",
+			"DTSTART;TZID=America/Los_Angeles:20170929T090000",
+			"DTEND;TZID=America/Los_Angeles:20170929T095000",
+			"RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;UNTIL=20171209T075959Z",
+			"EXDATE;TZID=America/Los_Angeles:20171110T090000",
+			"EXDATE;TZID=America/Los_Angeles:20171124T090000",
+			"UID:2017-92-34000-final@peterplanner.com",
+			"DTSTART;TZID=America/Los_Angeles:20171211T080000",
+		}, []string{
+			"EXDATE;TZID=America/Los_Angeles:20171123T090000",
+		}},
+		{"discussion", "34010", []string{
+			"DTSTART;TZID=America/Los_Angeles:20170928T140000",
+			"RRULE:FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20171209T075959Z",
+			"EXDATE;TZID=America/Los_Angeles:20171123T140000",
+		}, []string{
+			"EXDATE;TZID=America/Los_Angeles:20171110T140000",
+			"EXDATE;TZID=America/Los_Angeles:20171124T140000",
+			"-final@peterplanner.com",
+		}},
+		{"unscheduled", "34020", []string{
+			"BEGIN:VCALENDAR",
+		}, []string{
+			"BEGIN:VEVENT",
+		}},
+	}
+	for _, test := range tests {
+		ics, err := ExportICS(courses, "2017-92", []string{test.code}, &calendar, clock)
+		if err != nil {
+			t.Errorf("%v: %v", test.name, err)
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n")
+		for _, line := range lines {
+			if len(line) > 75 {
+				t.Errorf("%v: line longer than 75 octets: %q", test.name, line)
+			}
+		}
+		unfolded := strings.Replace(ics, "\r\n ", "", -1)
+		for _, s := range test.contains {
+			if !strings.Contains(unfolded, s) {
+				t.Errorf("%v: missing %q", test.name, s)
+			}
+		}
+		for _, s := range test.excludes {
+			if strings.Contains(unfolded, s) {
+				t.Errorf("%v: unexpected %q", test.name, s)
+			}
+		}
+	}
+}
+
+func TestExportICSErrors(t *testing.T) {
+	courses := map[string]types.Course{"COMPSCI161": {Department: "COMPSCI", Number: "161", Classes: map[string][]types.Class{"2017-92": {{Code: "34000"}}}}}
+	calendar := testCalendar()
+	clock := types.FixedClock{Time: time.Date(2017, time.September, 1, 12, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name  string
+		term  string
+		codes []string
+	}{
+		{"unknown code", "2017-92", []string{"99999"}},
+		{"term without calendar dates", "2018-03", []string{"34000"}},
+	}
+	for _, test := range tests {
+		if _, err := ExportICS(courses, test.term, test.codes, &calendar, clock); err == nil {
+			t.Errorf("%v: ExportICS succeeded", test.name)
+		}
+	}
+}
@@ -25,9 +25,14 @@ import (
 	"flag"
 	"fmt"
 	"github.com/beevik/etree"
+	"github.com/nicolasgomollon/peterplanner/audits"
 	"github.com/nicolasgomollon/peterplanner/database"
+	"github.com/nicolasgomollon/peterplanner/exporters"
 	"github.com/nicolasgomollon/peterplanner/helpers"
 	"github.com/nicolasgomollon/peterplanner/parsers"
+	"github.com/nicolasgomollon/peterplanner/planners"
+	"github.com/nicolasgomollon/peterplanner/schedulers"
+	"github.com/nicolasgomollon/peterplanner/search"
 	"github.com/nicolasgomollon/peterplanner/types"
 	"golang.org/x/net/html/charset"
 	"html"
@@ -35,12 +40,14 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
-	"strconv"
+	"sort"
 	"strings"
 	"time"
 )
 
 const DegreeWorksURL = "https://www.reg.uci.edu/dgw/IRISLink.cgi"
+const CataloguePath = "/var/www/registrar/catalogue.json"
+const SimilarityPath = "/var/www/registrar/similar.json"
 
 func fetchStudentID(cookie string) (string, error) {
 	body := "SERVICE=SCRIPTER&SCRIPT=SD2STUCON"
@@ -118,26 +125,26 @@ func fetchBasicXML(studentID string, cookie string) (string, error) {
 	return responseXML, nil
 }
 
-func readFromString(contentsXML string, outputJSON bool) {
+func readFromString(contentsXML string, report func(doc *etree.Document)) {
 	doc := etree.NewDocument()
 	doc.ReadSettings.CharsetReader = charset.NewReaderLabel
 	if err := doc.ReadFromString(contentsXML); err != nil {
 		panic(err)
 	}
-	parse(doc, outputJSON)
+	report(doc)
 }
 
-func readFromFile(fileName string, outputJSON bool) {
+func readFromFile(fileName string, report func(doc *etree.Document)) {
 	doc := etree.NewDocument()
 	doc.ReadSettings.CharsetReader = charset.NewReaderLabel
 	if err := doc.ReadFromFile(fileName); err != nil {
 		panic(err)
 	}
-	parse(doc, outputJSON)
+	report(doc)
 }
 
 func GetCatalogue() (types.Catalogue, error) {
-	b, err := ioutil.ReadFile("/var/www/registrar/catalogue.json")
+	b, err := ioutil.ReadFile(CataloguePath)
 	if err != nil {
 		panic(err)
 	}
@@ -146,77 +153,399 @@ func GetCatalogue() (types.Catalogue, error) {
 	if err != nil {
 		return types.Catalogue{}, err
 	}
+	// The similarity index is built separately with -index, and may not
+	// exist yet.
+	if b, err := ioutil.ReadFile(SimilarityPath); err == nil {
+		if err := json.Unmarshal(b, &catalogue.Similar); err != nil {
+			return types.Catalogue{}, err
+		}
+	}
 	return catalogue, nil
 }
 
-func parse(doc *etree.Document, outputJSON bool) {
+// buildSimilarityIndex writes the similarity index to its own file, leaving
+// the catalogue as it was scraped.
+func buildSimilarityIndex() {
+	catalogue, err := GetCatalogue()
+	if err != nil {
+		panic(err)
+	}
+	exportJSON, err := json.Marshal(search.BuildSimilarityIndex(catalogue.Courses))
+	if err != nil {
+		panic(err)
+	}
+	err = ioutil.WriteFile(SimilarityPath, exportJSON, 0644)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func suggestCourses(liked string, student *types.Student, catalogue *types.Catalogue, top int, outputJSON bool) {
+	keys := make([]string, 0)
+	for _, key := range strings.Split(liked, ",") {
+		if key = strings.Replace(strings.ToUpper(key), " ", "", -1); len(key) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	suggestions := search.Suggest(catalogue, student, keys, top)
+	if !outputJSON {
+		for _, suggestion := range suggestions {
+			course := catalogue.Courses[suggestion.Key]
+			fmt.Printf("%-12s %.3f  %-50s %v\n", suggestion.Key, suggestion.Score, course.Title, suggestion.Requirement)
+		}
+	} else {
+		exportJSON, err := json.Marshal(suggestions)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(exportJSON))
+	}
+}
+
+func GetCalendar() (types.Calendar, error) {
+	return types.CalendarFromFile("/var/www/registrar/calendar.json")
+}
+
+func exportICS(codes string, yearTerm string, clock types.Clock) {
+	catalogue, err := GetCatalogue()
+	if err != nil {
+		panic(err)
+	}
+	calendar, err := GetCalendar()
+	if err != nil {
+		panic(err)
+	}
+	if len(yearTerm) == 0 {
+		yearTerm = types.CurrentTerm(catalogue.Terms, clock)
+	}
+	ics, err := exporters.ExportICS(catalogue.Courses, yearTerm, strings.Split(codes, ","), &calendar, clock)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(ics)
+}
+
+func generateSchedules(keys string, yearTerm string, prefsPath string, top int, outputJSON bool, clock types.Clock) {
+	catalogue, err := GetCatalogue()
+	if err != nil {
+		panic(err)
+	}
+	if len(yearTerm) == 0 {
+		yearTerm = types.CurrentTerm(catalogue.Terms, clock)
+	}
+	
+	prefs := schedulers.DefaultPreferences
+	if len(prefsPath) > 0 {
+		prefs, err = schedulers.PreferencesFromFile(prefsPath)
+		if err != nil {
+			panic(err)
+		}
+	}
+	schedules, err := schedulers.TopSchedules(catalogue.Courses, yearTerm, strings.Split(keys, ","), prefs, top)
+	if err != nil {
+		panic(err)
+	}
+	
+	if !outputJSON {
+		for i, schedule := range schedules {
+			fmt.Printf("Schedule %d (score %.2f, %d days, %v between classes):\n", i+1, schedule.Score, schedule.Days(), schedule.Gaps())
+			for _, section := range schedule.Sections {
+				class := section.Class
+				fmt.Printf("    %-12s %v %v %-4s %-20s %-10s %v\n", section.Course, class.Code, class.Type, class.Section, class.Instructor, class.Place, class.Status)
+			}
+		}
+	} else {
+		exportJSON, err := json.Marshal(schedules)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(exportJSON))
+	}
+}
+
+func loadStudent(doc *etree.Document, clock types.Clock) (types.Student, types.Catalogue) {
 	catalogue, err := GetCatalogue()
 	if err != nil {
 		panic(err)
 	}
 	
 	student := parsers.Parse(doc, &catalogue)
-	student.Terms = catalogue.Terms
+	student.Terms = studentTerms(catalogue.Terms, clock)
+	return student, catalogue
+}
+
+// studentTerms lists the catalogue's terms up to the current one, most recent
+// first, so that the first term is the current one as of the clock's date.
+func studentTerms(terms []string, clock types.Clock) []string {
+	current := types.CurrentTerm(terms, clock)
+	studentTerms := []string{current}
+	for _, term := range terms {
+		if term < current {
+			studentTerms = append(studentTerms, term)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(studentTerms[1:])))
+	return studentTerms
+}
+
+func generatePlan(doc *etree.Document, clock types.Clock, unitCap float64, startTerm string, outputJSON bool) {
+	student, catalogue := loadStudent(doc, clock)
+	if len(startTerm) == 0 {
+		startTerm = types.NextTerm(student.Terms[0])
+	}
+	plan := planners.GeneratePlan(&student, &catalogue, planners.PlanOptions{StartTerm: startTerm, UnitCap: unitCap})
+	
+	if !outputJSON {
+		for _, term := range plan.Terms {
+			fmt.Printf("%v (%v units):\n", types.TermName(term.Term), term.Units)
+			for _, planned := range term.Courses {
+				fmt.Printf("    %v (%v units)\n", planned.Course, planned.Units)
+				for _, reason := range planned.Reasons {
+					fmt.Printf("        - %v\n", reason)
+				}
+			}
+		}
+		if len(plan.Unscheduled) > 0 {
+			fmt.Println("Unscheduled:")
+			for _, planned := range plan.Unscheduled {
+				fmt.Printf("    %v (%v units)\n", planned.Course, planned.Units)
+				for _, reason := range planned.Reasons {
+					fmt.Printf("        - %v\n", reason)
+				}
+			}
+		}
+	} else {
+		exportJSON, err := json.Marshal(plan)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(exportJSON))
+	}
+}
+
+func checkPlan(doc *etree.Document, clock types.Clock, planPath string, unitCap float64, outputJSON bool) {
+	student, catalogue := loadStudent(doc, clock)
+	plan, err := planners.PlanFromFile(planPath)
+	if err != nil {
+		panic(err)
+	}
+	check := planners.CheckPlan(&student, &catalogue, plan, unitCap)
+	
+	if !outputJSON {
+		for _, term := range check.Terms {
+			fmt.Printf("%v (%v units):\n", types.TermName(term.Term), term.Units)
+			if len(term.Findings) == 0 {
+				fmt.Println("    ✓ No issues found.")
+			}
+			for _, finding := range term.Findings {
+				printFinding(finding)
+			}
+		}
+		fmt.Println("Requirements:")
+		for _, finding := range check.Requirements {
+			printFinding(finding)
+		}
+	} else {
+		exportJSON, err := json.Marshal(check)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(exportJSON))
+	}
+}
+
+func matchRequirements(doc *etree.Document, clock types.Clock, outputJSON bool) {
+	student, _ := loadStudent(doc, clock)
+	report := audits.MatchRequirements(&student, audits.SharingRestrictions(&student))
+	
+	if !outputJSON {
+		fmt.Printf("DegreeWorks's placement completes %d rules. The best assignment completes %d rules.\n", report.AuditCompletedRules, report.CompletedRules)
+		if report.Better {
+			fmt.Println("A better assignment exists:")
+			for _, assignment := range report.Assignments {
+				fmt.Printf("    %v: %v [%d] ← %v\n", assignment.Block, assignment.Rule, assignment.Requirement+1, strings.Join(assignment.Courses, ", "))
+			}
+		}
+		if report.Truncated {
+			fmt.Println("Some rules had too many ways to complete them to try them all; a better assignment may exist.")
+		}
+	} else {
+		exportJSON, err := json.Marshal(report)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(exportJSON))
+	}
+}
+
+func checkConsistency(doc *etree.Document, clock types.Clock, outputJSON bool) {
+	student, _ := loadStudent(doc, clock)
+	report := audits.CheckConsistency(&student)
+	
+	if !outputJSON {
+		if len(report.Disagreements) == 0 {
+			fmt.Println("Local evaluation agrees with DegreeWorks.")
+		} else {
+			fmt.Printf("%d disagreement(s) with DegreeWorks:\n", len(report.Disagreements))
+			for _, discrepancy := range report.Disagreements {
+				fmt.Printf("    ✗ %v\n", discrepancy)
+			}
+		}
+		if len(report.Pending) > 0 {
+			fmt.Printf("%d in progress, pending grades:\n", len(report.Pending))
+			for _, discrepancy := range report.Pending {
+				fmt.Printf("    … %v\n", discrepancy)
+			}
+		}
+	} else {
+		exportJSON, err := json.Marshal(report)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(exportJSON))
+	}
+}
+
+func minimumCourseSet(doc *etree.Document, clock types.Clock, outputJSON bool) {
+	student, _ := loadStudent(doc, clock)
+	result := planners.MinimumCourseSet(&student)
+	
+	if !outputJSON {
+		method := "heuristic"
+		if result.Exact {
+			method = "exact"
+		}
+		fmt.Printf("Fewest courses to finish all requirements: %d (%v)\n", result.Size, method)
+		for i, set := range result.Sets {
+			fmt.Printf("    %d. %v\n", i+1, strings.Join(set, ", "))
+		}
+		for _, label := range result.Uncoverable {
+			fmt.Printf("    ! %v lists fewer options than it requires\n", label)
+		}
+	} else {
+		exportJSON, err := json.Marshal(result)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(exportJSON))
+	}
+}
+
+func pathTo(doc *etree.Document, clock types.Clock, target string, startTerm string, outputJSON bool) {
+	student, catalogue := loadStudent(doc, clock)
+	if len(startTerm) == 0 {
+		startTerm = types.NextTerm(student.Terms[0])
+	}
+	path := planners.PathTo(&student, &catalogue, target, startTerm)
+	
+	if !outputJSON {
+		if path.Reachable {
+			fmt.Printf("Eligible for %v in %v:\n", path.Target, types.TermName(path.EligibleTerm))
+			for _, step := range path.Steps {
+				fmt.Printf("    %-12s %v\n", types.TermName(step.Term), strings.Join(step.Courses, ", "))
+			}
+		} else {
+			fmt.Printf("Unable to find a path to %v.\n", path.Target)
+		}
+		for _, note := range path.Notes {
+			fmt.Printf("    ! %v\n", note)
+		}
+	} else {
+		exportJSON, err := json.Marshal(path)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(exportJSON))
+	}
+}
+
+func printFinding(finding planners.Finding) {
+	icon := "-"
+	switch finding.Severity {
+	case planners.SeverityError:
+		icon = "✗"
+	case planners.SeverityWarning:
+		icon = "!"
+	case planners.SeverityInfo:
+		icon = "✓"
+	}
+	if len(finding.Course) > 0 {
+		fmt.Printf("    %v %v: %v\n", icon, finding.Course, finding.Message)
+	} else {
+		fmt.Printf("    %v %v\n", icon, finding.Message)
+	}
+}
+
+func searchCourses(query search.Query, student *types.Student, catalogue *types.Catalogue, clock types.Clock, outputJSON bool) {
+	results := search.Search(catalogue, student, query, clock)
+	if !outputJSON {
+		for _, result := range results {
+			course := result.Course
+			title := course.Title
+			if len(title) == 0 {
+				title = course.ShortTitle
+			}
+			fmt.Printf("%-12s %-50s %v units  GE: %v\n", result.Key, title, course.Units, strings.Join(course.GE, ", "))
+		}
+	} else {
+		exportJSON, err := json.Marshal(results)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(exportJSON))
+	}
+}
+
+func parse(doc *etree.Document, clock types.Clock, unitCap float64, outputJSON bool) {
+	student, catalogue := loadStudent(doc, clock)
 	yearTerm := student.Terms[0]
 	
 	if !outputJSON {
+		printHeader(&student)
+		shortlists := planners.Recommend(&student, &catalogue, yearTerm, 0)
+		scores := make(map[string]float64, 0)
+		for _, r := range shortlists[0].Recommendations {
+			scores[r.Course] = r.Score
+		}
+		for _, shortlist := range shortlists {
+			fmt.Printf("Recommended for %v:\n", types.TermName(shortlist.Term))
+			for i, r := range shortlist.Recommendations {
+				if i == planners.ShortlistLength {
+					break
+				}
+				fmt.Printf("    %d. %-12s satisfies %d, unlocks %d, offered: %v\n", i+1, r.Course, len(r.Satisfies), r.Unlocks, r.Offered)
+			}
+		}
+		
 		for _, block := range student.Blocks {
 			fmt.Printf("%v: %v\n", block.ReqType, block.Title)
-			for _, rule := range block.Rules {
-				if rule.IsCompleted(&student) {
-					fmt.Printf("✓ %v\n", rule.Label)
-					continue
-				}
-				if (rule.Required == 1) && (len(rule.Requirements) == 1) {
-					fmt.Printf("- %v\n", rule.Label)
-				} else {
-					fmt.Printf("- %v (%v)\n", rule.Label, rule.Required)
-				}
-				for _, req := range rule.Requirements {
-					if req.IsCompleted() {
-						continue
-					}
-					fmt.Printf("    - %d classes remaining in:\n", req.Required)
-					for _, option := range req.Options {
-						course := student.Courses[option]
-						termsOffered := make(map[string][]int, 0)
-						for k := range course.Classes {
-							t := "--"
-							switch {
-							case types.IsFQ(k):
-								t = "F"
-							case types.IsWQ(k):
-								t = "W"
-							case types.IsSQ(k):
-								t = "S"
-							}
-							y, _ := strconv.Atoi(k[0:4])
-							years := termsOffered[t]
-							if years == nil {
-								years = make([]int, 0)
-							}
-							years = append(years, y)
-							termsOffered[t] = years
-						}
-						cleared := course.ClearedPrereqs(&student)
-						icon := "✗"
-						if cleared {
-							icon = "✓"
-						}
-						
-						fmt.Printf("        %-35s   offered: %v\n", fmt.Sprintf("%v %v %v: %v", icon, course.Department, course.Number, course.Title), termsOffered)
-						if cleared {
-							for _, class := range course.Classes[yearTerm] {
-								fmt.Printf("            %v %v %v %v\n", class.Code, class.Type, class.Section, class.Instructor)
-							}
-						} else {
-							printArray(course.Prerequisites)
-						}
-					}
+			for _, message := range block.Check(&student) {
+				fmt.Printf("! %v\n", message)
+			}
+			for _, conditional := range block.Conditionals {
+				status := "else branch applies"
+				if conditional.Satisfied {
+					status = "applies"
 				}
+				fmt.Printf("? if %v (%v)\n", conditional.Condition, status)
+			}
+			for _, rule := range block.Rules {
+				printRule(&student, rule, "", yearTerm, scores)
 			}
 		}
+		graduation := planners.EstimateGraduation(&student, &catalogue, planners.GraduationOptions{StartTerm: types.NextTerm(yearTerm), Pace: unitCap})
+		earliest := "unknown"
+		if len(graduation.EarliestTerm) > 0 {
+			earliest = types.TermName(graduation.EarliestTerm)
+		}
+		fmt.Printf("Estimated graduation: %v (%v units remaining, %v upper division)\n", earliest, graduation.UnitsRemaining, graduation.UpperDivisionUnitsRemaining)
+		for _, constraint := range graduation.Constraints {
+			fmt.Printf("    ! %v delays graduation: %v\n", constraint.Course, strings.Join(constraint.Reasons, "; "))
+		}
 	} else {
+		graduation := planners.EstimateGraduation(&student, &catalogue, planners.GraduationOptions{StartTerm: types.NextTerm(yearTerm), Pace: unitCap})
+		student.Graduation = &graduation
+		student.Recommendations = planners.Recommend(&student, &catalogue, yearTerm, planners.ShortlistLength)
 		exportJSON, err := json.Marshal(student)
 		if err != nil {
 			panic(err)
@@ -225,9 +554,131 @@ func parse(doc *etree.Document, outputJSON bool) {
 	}
 }
 
-func printArray(prereqs [][]string) {
+func printHeader(student *types.Student) {
+	fmt.Printf("%v (%v)\n", student.Name, student.StudentID)
+	if len(student.Degree.Code) > 0 {
+		fmt.Printf("Degree: %v, catalog year %v\n", strings.TrimSpace(student.Degree.Code + " " + student.Degree.Literal), student.CatalogYear())
+	}
+	goals := []struct {
+		label string
+		goals []types.Goal
+	}{
+		{"Majors", student.Majors()},
+		{"Minors", student.Minors()},
+		{"Concentrations", student.Concentrations()},
+	}
+	for _, g := range goals {
+		if len(g.goals) == 0 {
+			continue
+		}
+		names := make([]string, 0)
+		for _, goal := range g.goals {
+			names = append(names, goal.String())
+		}
+		fmt.Printf("%v: %v\n", g.label, strings.Join(names, ", "))
+	}
+	for _, advisor := range student.Advisors {
+		fmt.Printf("Advisor: %v <%v>\n", advisor.Name, advisor.Email)
+	}
+	for _, hold := range student.Holds {
+		fmt.Printf("! Hold %v: %v\n", hold.Code, hold.Description)
+	}
+	for _, note := range student.Notes {
+		fmt.Printf("Note (%v, %v): %v\n", note.Who, note.Date, note.Text)
+	}
+}
+
+func printRule(student *types.Student, rule types.Rule, indent string, yearTerm string, scores map[string]float64) {
+	requirements, messages := rule.Check(student)
+	if rule.IsCompleted(student) {
+		fmt.Printf("%v✓ %v\n", indent, rule.Label)
+		for _, exception := range rule.Exceptions {
+			fmt.Printf("%v    ※ %v\n", indent, exception)
+		}
+		for _, message := range messages {
+			fmt.Printf("%v    ! %v\n", indent, message)
+		}
+		return
+	}
+	switch {
+	case (rule.Kind == types.NoncourseRule) && !rule.Status.Reported:
+		fmt.Printf("%v? %v (status unknown)\n", indent, rule.Label)
+	case rule.Kind == types.NoncourseRule:
+		fmt.Printf("%v- %v (%v%% complete)\n", indent, rule.Label, rule.Status.PercentComplete)
+	case (rule.Kind == types.BlockRule) || (rule.Kind == types.BlocktypeRule):
+		fmt.Printf("%v- %v (see %v)\n", indent, rule.Label, strings.TrimSpace(rule.BlockType + " " + rule.BlockValue))
+	case (rule.Required == 1) && (len(rule.Requirements) + len(rule.Rules) == 1):
+		fmt.Printf("%v- %v\n", indent, rule.Label)
+	default:
+		fmt.Printf("%v- %v (%v of %v)\n", indent, rule.Label, rule.Required, len(rule.Requirements) + len(rule.Rules))
+	}
+	for _, exception := range rule.Exceptions {
+		fmt.Printf("%v    ※ %v\n", indent, exception)
+	}
+	for _, message := range messages {
+		fmt.Printf("%v    ! %v\n", indent, message)
+	}
+	for _, child := range rule.Rules {
+		printRule(student, child, indent + "    ", yearTerm, scores)
+	}
+	for _, req := range requirements {
+		if req.IsCompleted() {
+			continue
+		}
+		if req.Credits > 0.0 {
+			fmt.Printf("%v    - %v, remaining in:\n", indent, req.Progress())
+		} else {
+			fmt.Printf("%v    - %d classes remaining in:\n", indent, req.Required)
+		}
+		for _, pattern := range req.Patterns {
+			if pattern.Department == "@" {
+				fmt.Printf("%v        any course matching %v\n", indent, pattern)
+			}
+		}
+		options := make([]string, len(req.Options))
+		copy(options, req.Options)
+		sort.SliceStable(options, func(i, j int) bool {
+			return scores[options[i]] > scores[options[j]]
+		})
+		for _, option := range options {
+			course := student.Courses[option]
+			termsOffered := course.TermsOffered()
+			forecasts := make([]string, 0)
+			term := yearTerm
+			for i := 0; i < 3; i++ {
+				term = types.NextTerm(term)
+				forecast := course.PredictOffering(term, student.Terms)
+				if forecast.Unknown {
+					forecasts = append(forecasts, fmt.Sprintf("%v ?", types.TermName(term)))
+					continue
+				}
+				forecasts = append(forecasts, fmt.Sprintf("%v %.0f%%", types.TermName(term), forecast.Probability*100.0))
+			}
+			cleared := course.ClearedPrereqs(student)
+			icon := "✗"
+			if cleared {
+				icon = "✓"
+			}
+			
+			fmt.Printf("%v        %-35s   offered: %v   forecast: %v (%v)\n", indent, fmt.Sprintf("%v %v %v: %v", icon, course.Department, course.Number, course.Title), termsOffered, strings.Join(forecasts, ", "), course.OfferingPattern(student.Terms))
+			if cleared {
+				for _, bundle := range course.ClassBundles(yearTerm) {
+					sections := make([]string, 0)
+					for _, class := range bundle {
+						sections = append(sections, fmt.Sprintf("%v %v %v %v", class.Code, class.Type, class.Section, class.Instructor))
+					}
+					fmt.Printf("%v            %v\n", indent, strings.Join(sections, " + "))
+				}
+			} else {
+				printArray(indent, course.Prerequisites)
+			}
+		}
+	}
+}
+
+func printArray(indent string, prereqs [][]string) {
 	for i, prereqInter := range prereqs {
-		spaces := "            "
+		spaces := indent + "            "
 		sep := ","
 		if i == (len(prereqs) - 1) {
 			sep = ""
@@ -244,9 +695,121 @@ func main() {
 	studentIDptr := flag.String("studentID", "", "Fetch DegreeWorks XML file for the specified student ID.")
 	cookiePtr := flag.String("cookie", "", "Fetch DegreeWorks XML file using specified cookies.")
 	jsonPtr := flag.Bool("json", false, "Output the result in JSON format.")
+	icsPtr := flag.String("ics", "", "Export the specified comma-separated class codes as an iCalendar (.ics) file.")
+	schedulePtr := flag.String("schedule", "", "Generate conflict-free schedules for the specified comma-separated course keys.")
+	prefsPtr := flag.String("prefs", "", "Rank generated schedules using the preferences in the specified JSON file.")
+	topPtr := flag.Int("top", 10, "Limit schedules and suggestions to the specified number of results.")
+	planPtr := flag.Bool("plan", false, "Generate a term-by-term plan to graduation.")
+	checkPtr := flag.String("check", "", "Validate the multi-term plan in the specified JSON or YAML file.")
+	unitsPtr := flag.Float64("units", planners.DefaultUnitCap, "Plan at most the specified number of units per term.")
+	assignPtr := flag.Bool("assign", false, "Find the assignment of completed courses to requirements that completes the most rules.")
+	crossCheckPtr := flag.Bool("cross-check", false, "Report every rule and block where local evaluation disagrees with DegreeWorks.")
+	coverPtr := flag.Bool("cover", false, "Find the fewest courses that finish all remaining requirements.")
+	pathToPtr := flag.String("path-to", "", "Find the shortest sequence of terms and courses to become eligible for the specified course.")
+	searchPtr := flag.Bool("search", false, "Search the catalogue using the search filter flags below.")
+	keywordsPtr := flag.String("keywords", "", "Search filter: rank courses by the specified keywords.")
+	deptPtr := flag.String("dept", "", "Search filter: department (e.g. COMPSCI).")
+	levelPtr := flag.String("level", "", "Search filter: course level (lower, upper or graduate).")
+	minUnitsPtr := flag.Float64("min-units", 0.0, "Search filter: minimum number of units.")
+	maxUnitsPtr := flag.Float64("max-units", 0.0, "Search filter: maximum number of units.")
+	gePtr := flag.String("ge", "", "Search filter: GE category (e.g. II or Vb).")
+	daysPtr := flag.String("days", "", "Search filter: meet only on the specified days (e.g. TuTh).")
+	afterPtr := flag.String("after", "", "Search filter: start no earlier than the specified time (HH:MM).")
+	beforePtr := flag.String("before", "", "Search filter: end no later than the specified time (HH:MM).")
+	instructorPtr := flag.String("instructor", "", "Search filter: taught by the specified instructor.")
+	clearedPtr := flag.Bool("cleared", false, "Search filter: only courses whose prerequisites the student has cleared.")
+	indexPtr := flag.Bool("index", false, "Build the course similarity index and store it in " + SimilarityPath + ".")
+	similarPtr := flag.String("similar", "", "Suggest courses similar to the specified comma-separated course keys.")
+	suggestPtr := flag.Bool("suggest", false, "Suggest options of unfinished requirements similar to -similar courses or to completed ones.")
+	termPtr := flag.String("term", "", "Use the specified term (e.g. 2017-92) instead of the current term.")
+	asOfPtr := flag.String("as-of", "", "Evaluate all date-dependent logic as of the specified date (YYYY-MM-DD).")
 	flag.Parse()
+	
+	var clock types.Clock = types.SystemClock{}
+	if len(*asOfPtr) > 0 {
+		asOf, err := types.ParseAsOf(*asOfPtr)
+		if err != nil {
+			panic(err)
+		}
+		clock = asOf
+		types.DefaultClock = asOf
+	}
+	
+	query := search.Query{
+		Keywords:   *keywordsPtr,
+		Department: *deptPtr,
+		Level:      *levelPtr,
+		MinUnits:   *minUnitsPtr,
+		MaxUnits:   *maxUnitsPtr,
+		GE:         *gePtr,
+		Term:       *termPtr,
+		After:      *afterPtr,
+		Before:     *beforePtr,
+		Instructor: *instructorPtr,
+		Cleared:    *clearedPtr,
+	}
+	if len(*daysPtr) > 0 {
+		query.Days = types.ParseDays(*daysPtr)
+	}
+	
+	report := func(doc *etree.Document) {
+		parse(doc, clock, *unitsPtr, *jsonPtr)
+	}
+	if *planPtr {
+		report = func(doc *etree.Document) {
+			generatePlan(doc, clock, *unitsPtr, *termPtr, *jsonPtr)
+		}
+	} else if len(*checkPtr) > 0 {
+		report = func(doc *etree.Document) {
+			checkPlan(doc, clock, *checkPtr, *unitsPtr, *jsonPtr)
+		}
+	} else if *assignPtr {
+		report = func(doc *etree.Document) {
+			matchRequirements(doc, clock, *jsonPtr)
+		}
+	} else if *crossCheckPtr {
+		report = func(doc *etree.Document) {
+			checkConsistency(doc, clock, *jsonPtr)
+		}
+	} else if *coverPtr {
+		report = func(doc *etree.Document) {
+			minimumCourseSet(doc, clock, *jsonPtr)
+		}
+	} else if len(*pathToPtr) > 0 {
+		report = func(doc *etree.Document) {
+			pathTo(doc, clock, *pathToPtr, *termPtr, *jsonPtr)
+		}
+	} else if *suggestPtr {
+		report = func(doc *etree.Document) {
+			student, catalogue := loadStudent(doc, clock)
+			suggestCourses(*similarPtr, &student, &catalogue, *topPtr, *jsonPtr)
+		}
+	} else if *searchPtr {
+		report = func(doc *etree.Document) {
+			student, catalogue := loadStudent(doc, clock)
+			searchCourses(query, &student, &catalogue, clock, *jsonPtr)
+		}
+	}
 
-	if len(*cookiePtr) > 0 {
+	if *indexPtr {
+		buildSimilarityIndex()
+	} else if len(*icsPtr) > 0 {
+		exportICS(*icsPtr, *termPtr, clock)
+	} else if *searchPtr && (len(*cookiePtr) == 0) && (len(*uidPtr) == 0) && (len(*studentIDptr) == 0) {
+		catalogue, err := GetCatalogue()
+		if err != nil {
+			panic(err)
+		}
+		searchCourses(query, nil, &catalogue, clock, *jsonPtr)
+	} else if (len(*similarPtr) > 0) && !*suggestPtr {
+		catalogue, err := GetCatalogue()
+		if err != nil {
+			panic(err)
+		}
+		suggestCourses(*similarPtr, nil, &catalogue, *topPtr, *jsonPtr)
+	} else if len(*schedulePtr) > 0 {
+		generateSchedules(*schedulePtr, *termPtr, *prefsPtr, *topPtr, *jsonPtr, clock)
+	} else if len(*cookiePtr) > 0 {
 		if len(*studentIDptr) == 0 {
 			studentID, err := fetchStudentID(*cookiePtr)
 			if err != nil {
@@ -263,6 +826,8 @@ func main() {
 		
 		studentExists, uid, err := database.RowExists(dbConn, "SELECT `uid` FROM `accounts` WHERE `studentID`=? LIMIT 1", *studentIDptr)
 		if !studentExists && (err == nil) {
+			// The uid must not be guessable, so it uses the wall clock even
+			// when -as-of is given.
 			timestamp := time.Now().Format(time.RFC3339)
 			uid = fmt.Sprintf("%v|%v", *studentIDptr, timestamp)
 			h := sha1.New()
@@ -308,12 +873,12 @@ func main() {
 		
 		studentExists, studentID, _ := database.RowExists(dbConn, "SELECT `studentID` FROM `accounts` WHERE `uid`=? LIMIT 1", *uidPtr)
 		if studentExists {
-			readFromFile(fmt.Sprintf("/var/www/reports/DGW_Report-%v.xsl", studentID), *jsonPtr)
+			readFromFile(fmt.Sprintf("/var/www/reports/DGW_Report-%v.xsl", studentID), report)
 		} else {
 			fmt.Println("{}")
 		}
 	} else if len(*studentIDptr) > 0 {
-		readFromFile(fmt.Sprintf("/var/www/reports/DGW_Report-%v.xsl", *studentIDptr), *jsonPtr)
+		readFromFile(fmt.Sprintf("/var/www/reports/DGW_Report-%v.xsl", *studentIDptr), report)
 	} else {
 		fmt.Println("No flags were specified. Use `-h` or `--help` flags to get help.")
 	}
@@ -25,6 +25,7 @@ import (
 	"github.com/nicolasgomollon/peterplanner/types"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -76,11 +77,20 @@ func ParseCatalogue(responseHTML string, courses *map[string]types.Course) {
 	r, _ = regexp.Compile(`(?s)<div class="courseblock">.*?<p class="courseblocktitle"><strong>(.*?)\.\s*(.*?)\..*?</strong></p>.*?<div class="courseblockdesc">.*?<p>(.*?)</p>.*?</div>`)
 	cs := r.FindAllStringSubmatch(coursesBlock, -1)
 	
+	u, _ := regexp.Compile(`(?s)<p class="courseblocktitle"><strong>.*?(\d+(?:\.\d+)?)(?:-\d+(?:\.\d+)?)? Units?\.`)
+	g, _ := regexp.Compile(`<p>\(((?:I{1,3}|IV|VI{0,3})[ab]?(?:,\s*(?:I{1,3}|IV|VI{0,3})[ab]?)*)\)\.?</p>`)
+	
 	for _, c := range cs {
 		number := s.ReplaceAllString(strings.ToUpper(Clean(c[1])), "")[len(dept):]
 		title := c[2]
 		description := c[3]
 		course := types.Course{Department: dept, Number: number, Title: title, Description: description}
+		if units := u.FindStringSubmatch(c[0]); len(units) > 0 {
+			course.Units, _ = strconv.ParseFloat(units[1], 64)
+		}
+		if ge := g.FindStringSubmatch(c[0]); len(ge) > 0 {
+			course.GE = strings.Split(strings.Replace(ge[1], " ", "", -1), ",")
+		}
 		(*courses)[course.Key()] = course
 	}
 }
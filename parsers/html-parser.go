@@ -151,12 +151,19 @@ func Clean(element string) string {
 }
 
 func parsedPrerequisites(rawPrereqs string) [][]string {
-	r, _ := regexp.Compile(`(?s) \( (?:coreq|recommended|min score = [\w+-]+) \)`)
+	r, _ := regexp.Compile(`(?s) \( (?:recommended|min score = [\w+-]+) \)`)
 	element := r.ReplaceAllString(rawPrereqs, "")
 	
 	r, _ = regexp.Compile(`(?s) \( min grade = ([\w+-]+) \)`)
 	element = r.ReplaceAllString(element, `|$1`)
 	
+	// Corequisites may be taken concurrently. See `types.ParsePrerequisite`.
+	r, _ = regexp.Compile(`(?s)((?:\|[\w+-]+)?) \( coreq \)`)
+	element = r.ReplaceAllStringFunc(element, func(match string) string {
+		grade := strings.TrimPrefix(strings.TrimSuffix(match, " ( coreq )"), "|")
+		return "|" + grade + "|COREQ"
+	})
+	
 	r, _ = regexp.Compile(`(?s)(\( | \))`)
 	element = r.ReplaceAllString(element, "")
 	
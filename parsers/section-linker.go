@@ -0,0 +1,135 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package parsers
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"strings"
+	"unicode"
+)
+
+/* WebSOC Section Linker */
+
+type sectionGroup struct {
+	primaries   []types.Class
+	secondaries map[string][]types.Class
+	kinds       []string
+}
+
+func (group *sectionGroup) add(class types.Class) {
+	kind := strings.TrimSpace(class.Type)
+	if _, ok := group.secondaries[kind]; !ok {
+		group.kinds = append(group.kinds, kind)
+	}
+	group.secondaries[kind] = append(group.secondaries[kind], class)
+}
+
+func newSectionGroup() *sectionGroup {
+	return &sectionGroup{primaries: make([]types.Class, 0), secondaries: make(map[string][]types.Class, 0), kinds: make([]string, 0)}
+}
+
+// sectionLetter returns the leading letter of a section, e.g. `A` for `A1`,
+// or an empty string for purely numeric sections.
+func sectionLetter(section string) string {
+	section = strings.TrimSpace(section)
+	if (len(section) > 0) && unicode.IsLetter(rune(section[0])) {
+		return strings.ToUpper(section[0:1])
+	}
+	return ""
+}
+
+// LinkSections infers which sections must be enrolled in together, following
+// the WebSOC conventions:
+//   - The type of the first listed section (usually `Lec`) is the primary type.
+//     Every other section (`Dis`, `Lab`, ...) is a secondary section.
+//   - A secondary section whose letter matches a primary section's letter
+//     (e.g. `Dis A1` and `Lec A`) belongs to that primary section.
+//   - Otherwise, a secondary section belongs to the run of consecutive primary
+//     sections listed directly before it (e.g. `Lec A`, `Lec B`, `Dis 1`, `Dis 2`).
+// Each bundle holds one primary section plus one section of every secondary
+// type in its group that applies to it, identified by class code.
+func LinkSections(classes []types.Class) [][]string {
+	bundles := make([][]string, 0)
+	if len(classes) == 0 {
+		return bundles
+	}
+	primaryType := strings.TrimSpace(classes[0].Type)
+	
+	groups := make([]*sectionGroup, 0)
+	byLetter := make(map[string]*sectionGroup, 0)
+	var current *sectionGroup
+	lastWasPrimary := false
+	for _, class := range classes {
+		if strings.TrimSpace(class.Type) == primaryType {
+			if (current == nil) || !lastWasPrimary {
+				current = newSectionGroup()
+				groups = append(groups, current)
+			}
+			current.primaries = append(current.primaries, class)
+			if letter := sectionLetter(class.Section); len(letter) > 0 {
+				byLetter[letter] = current
+			}
+			lastWasPrimary = true
+			continue
+		}
+		lastWasPrimary = false
+		if group, ok := byLetter[sectionLetter(class.Section)]; ok {
+			group.add(class)
+		} else if current != nil {
+			current.add(class)
+		}
+	}
+	
+	for _, group := range groups {
+		for _, primary := range group.primaries {
+			combinations := [][]string{{strings.TrimSpace(primary.Code)}}
+			primaryLetter := sectionLetter(primary.Section)
+			for _, kind := range group.kinds {
+				options := make([]string, 0)
+				for _, secondary := range group.secondaries[kind] {
+					// A lettered secondary section only pairs with the primary
+					// section of the same letter, e.g. `Dis B1` with `Lec B`.
+					letter := sectionLetter(secondary.Section)
+					if (len(letter) > 0) && (len(primaryLetter) > 0) && (byLetter[letter] == group) && (letter != primaryLetter) {
+						continue
+					}
+					options = append(options, strings.TrimSpace(secondary.Code))
+				}
+				if len(options) == 0 {
+					// None of this type belong to the primary section (e.g.
+					// `Lec B` when only `Dis A1` is listed), so it stands
+					// without one rather than dropping out of the bundles.
+					continue
+				}
+				expanded := make([][]string, 0)
+				for _, combination := range combinations {
+					for _, option := range options {
+						bundle := make([]string, len(combination), len(combination)+1)
+						copy(bundle, combination)
+						expanded = append(expanded, append(bundle, option))
+					}
+				}
+				combinations = expanded
+			}
+			bundles = append(bundles, combinations...)
+		}
+	}
+	return bundles
+}
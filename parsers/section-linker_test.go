@@ -0,0 +1,83 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package parsers
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"reflect"
+	"testing"
+)
+
+func TestLinkSections(t *testing.T) {
+	section := func(code, kind, sec string) types.Class {
+		return types.Class{Code: code, Type: kind, Section: sec}
+	}
+	tests := []struct {
+		name    string
+		classes []types.Class
+		want    [][]string
+	}{
+		{
+			"empty",
+			[]types.Class{},
+			[][]string{},
+		},
+		{
+			"lecture only",
+			[]types.Class{section("1", "Lec", "A"), section("2", "Lec", "B")},
+			[][]string{{"1"}, {"2"}},
+		},
+		{
+			"lettered discussions",
+			[]types.Class{section("1", "Lec", "A"), section("2", "Dis", "A1"), section("3", "Dis", "A2"), section("4", "Lec", "B"), section("5", "Dis", "B1")},
+			[][]string{{"1", "2"}, {"1", "3"}, {"4", "5"}},
+		},
+		{
+			"numbered discussions shared by a run of lectures",
+			[]types.Class{section("1", "Lec", "A"), section("2", "Lec", "B"), section("3", "Dis", "1"), section("4", "Dis", "2")},
+			[][]string{{"1", "3"}, {"1", "4"}, {"2", "3"}, {"2", "4"}},
+		},
+		{
+			"discussion and lab",
+			[]types.Class{section("1", "Lec", "A"), section("2", "Dis", "1"), section("3", "Lab", "1"), section("4", "Lab", "2")},
+			[][]string{{"1", "2", "3"}, {"1", "2", "4"}},
+		},
+		{
+			"separate lecture groups",
+			[]types.Class{section("1", "Lec", "1"), section("2", "Dis", "1"), section("3", "Lec", "2"), section("4", "Dis", "2")},
+			[][]string{{"1", "2"}, {"3", "4"}},
+		},
+		{
+			"lettered lecture without a matching secondary",
+			[]types.Class{section("1", "Lec", "A"), section("2", "Lec", "B"), section("3", "Dis", "A1")},
+			[][]string{{"1", "3"}, {"2"}},
+		},
+		{
+			"lettered lecture falls back to unlettered secondaries",
+			[]types.Class{section("1", "Lec", "A"), section("2", "Lec", "B"), section("3", "Dis", "A1"), section("4", "Dis", "5")},
+			[][]string{{"1", "3"}, {"1", "4"}, {"2", "4"}},
+		},
+	}
+	for _, test := range tests {
+		if got := LinkSections(test.classes); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v: LinkSections() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -119,11 +120,14 @@ func ParseWebSOC(yearTerm, responseTXT string, courses *map[string]types.Course)
 		instTkn := Token{}
 		timeTkn := Token{}
 		placeTkn := Token{}
+		finalTkn := Token{}
+		statusTkn := Token{}
 		
 		cDept := ""
 		cNum := ""
 		cTitle := ""
 		
+		parsed := make(map[string]bool, 0)
 		for scanner.Scan() {
 			line := scanner.Text()
 			if len(line) == 0 {
@@ -172,6 +176,11 @@ func ParseWebSOC(yearTerm, responseTXT string, courses *map[string]types.Course)
 				
 				placeTkn.Start = timeTkn.End + 1
 				placeTkn.End = strings.Index(line, "Final") - 1
+				
+				finalTkn.Start = placeTkn.End + 1
+				finalTkn.End = strings.Index(line, "Max") - 1
+				
+				statusTkn.Start = strings.Index(line, "Status")
 				continue
 			} else if len(line) < width {
 				// Handle `~ Same as 34030 (CompSci 113, Lec A).`
@@ -195,13 +204,25 @@ func ParseWebSOC(yearTerm, responseTXT string, courses *map[string]types.Course)
 				class.Time = types.ParseTime(cTimeParts[2])
 			}
 			class.Place = strings.TrimSpace(line[placeTkn.Start:placeTkn.End])
+			if (finalTkn.Start < finalTkn.End) && (finalTkn.End <= len(line)) {
+				class.Final = strings.TrimSpace(line[finalTkn.Start:finalTkn.End])
+			}
+			if (statusTkn.Start >= 0) && (statusTkn.Start < len(line)) {
+				class.Status = strings.ToUpper(strings.TrimSpace(line[statusTkn.Start:]))
+			}
 			//fmt.Printf("`%v` `%v` `%v` `%v` `%v` `%v` `%v`\n", class.Code, class.Type, class.Section, class.Instructor, class.Days, class.Time, class.Place)
 			
+			cUnits, _ := strconv.ParseFloat(strings.TrimSpace(line[untTkn.Start:untTkn.End]), 64)
+			
 			k := strings.Replace(cDept + cNum, " ", "", -1)
+			parsed[k] = true
 			if course, ok := (*courses)[k]; ok {
 				if len(course.ShortTitle) == 0 {
 					course.ShortTitle = cTitle
 				}
+				if course.Units == 0.0 {
+					course.Units = cUnits
+				}
 				classesMap := course.Classes
 				if classesMap == nil {
 					classesMap = make(map[string][]types.Class, 0)
@@ -215,7 +236,7 @@ func ParseWebSOC(yearTerm, responseTXT string, courses *map[string]types.Course)
 				course.Classes = classesMap
 				(*courses)[k] = course
 			} else {
-				course := types.Course{Department: cDept, Number: cNum, ShortTitle: cTitle}
+				course := types.Course{Department: cDept, Number: cNum, ShortTitle: cTitle, Units: cUnits}
 				classesMap := make(map[string][]types.Class, 0)
 				classes := make([]types.Class, 0)
 				classes = append(classes, class)
@@ -224,9 +245,23 @@ func ParseWebSOC(yearTerm, responseTXT string, courses *map[string]types.Course)
 				(*courses)[k] = course
 			}
 		}
+		linkParsedSections(yearTerm, parsed, courses)
 	}
 	if err := scanner.Err(); err != nil {
 		return err
 	}
 	return nil
 }
+
+func linkParsedSections(yearTerm string, parsed map[string]bool, courses *map[string]types.Course) {
+	for k := range parsed {
+		course := (*courses)[k]
+		bundlesMap := course.Bundles
+		if bundlesMap == nil {
+			bundlesMap = make(map[string][][]string, 0)
+		}
+		bundlesMap[yearTerm] = LinkSections(course.Classes[yearTerm])
+		course.Bundles = bundlesMap
+		(*courses)[k] = course
+	}
+}
@@ -40,30 +40,75 @@ func Parse(doc *etree.Document, catalogue *types.Catalogue) types.Student {
 	name := audit.SelectAttrValue("Stu_name", "ANTEATER, PETER THE")
 	email := audit.SelectAttrValue("Stu_email", "PTANTEATER@UCI.EDU")
 	student := types.Student{StudentID: studentID, Name: name, Email: email}
+	student.Audit = parseAuditInfo(audit)
 	
 	activeTerm := ""
+	goals := make([]types.Goal, 0)
 	deginfo := root.SelectElement("Deginfo")
 	if deginfo != nil {
 		degreeData := deginfo.SelectElement("DegreeData")
 		if degreeData != nil {
 			activeTerm = degreeData.SelectAttrValue("Actv_term", "")
+			student.Degree = parseDegree(degreeData)
+			if len(student.Degree.Code) > 0 {
+				goals = append(goals, types.Goal{Code: "DEGREE", Value: student.Degree.Code, Literal: student.Degree.Literal, CatalogYear: student.Degree.CatalogYear})
+			}
+		}
+		for _, goal := range deginfo.SelectElements("Goal") {
+			code := strings.ToUpper(goal.SelectAttrValue("Code", ""))
+			value := strings.ToUpper(goal.SelectAttrValue("Value", ""))
+			literal := elementValue(goal, "Value_literal", "Goal_literal", "Literal")
+			catalogYear := elementValue(goal, "Catalog_year", "Cat_yr")
+			goals = append(goals, types.Goal{Code: code, Value: value, Literal: literal, CatalogYear: catalogYear})
 		}
 	}
+	student.Goals = goals
+	// The catalog year decides which requirements apply, so conditions can
+	// test it like any other goal.
+	if catalogYear := student.CatalogYear(); len(catalogYear) > 0 {
+		goals = append(goals, types.Goal{Code: "CATYR", Value: catalogYear})
+		student.Goals = goals
+	}
 	
+	// Rules carry notes of their own, so only the header is searched.
+	student.Advisors = make([]types.Advisor, 0)
+	for _, a := range headerElements("Advisor", audit, deginfo) {
+		advisor := types.Advisor{}
+		advisor.Name = elementValue(a, "Advisor_name", "Name")
+		advisor.Email = elementValue(a, "Advisor_email", "Email")
+		advisor.Type = elementValue(a, "Advisor_type", "Type")
+		student.Advisors = append(student.Advisors, advisor)
+	}
+	student.Notes = make([]types.Note, 0)
+	for _, n := range headerElements("Note", audit, deginfo) {
+		student.Notes = append(student.Notes, parseNote(n))
+	}
+	student.Holds = make([]types.Hold, 0)
+	for _, h := range headerElements("Hold", audit, deginfo) {
+		hold := types.Hold{}
+		hold.Code = elementValue(h, "Hold_code", "Code")
+		hold.Description = elementValue(h, "Hold_desc", "Description")
+		student.Holds = append(student.Holds, hold)
+	}
+	
+	transcript := make(types.Transcript, 0)
 	clsinfo := root.SelectElement("Clsinfo")
 	if clsinfo != nil {
 		for _, class := range clsinfo.SelectElements("Class") {
-			cDept := class.SelectAttrValue("Discipline", "DEPT")
-			cNum := class.SelectAttrValue("Number", "0")
-			cTitle := class.SelectAttrValue("Course_title", "")
-			cTerm := class.SelectAttrValue("Term", "")
-			cInProgress := class.SelectAttrValue("In_progress", "N")
-			key := strings.Replace(strings.ToUpper(cDept + cNum), " ", "", -1)
-			if (cInProgress == "Y") && (cTerm > activeTerm) {
-				enrolled[key] = cTitle
+			entry := parseClass(class, transcript)
+			if entry.InProgress && (entry.Term > activeTerm) {
+				enrolled[entry.Course] = entry.Title
 			}
+			transcript = append(transcript, entry)
 		}
 	}
+	student.Transcript = transcript
+	
+	exceptions := make([]types.Exception, 0)
+	for _, e := range root.FindElements(".//Exception") {
+		exceptions = append(exceptions, parseException(e, catalogue, &courses))
+	}
+	student.Exceptions = exceptions
 	
 	for _, block := range root.SelectElements("Block") {
 		reqType := block.SelectAttrValue("Req_type", "unknown")
@@ -88,13 +133,21 @@ func Parse(doc *etree.Document, catalogue *types.Catalogue) types.Student {
 			parseProgram(block, catalogue, &courses, &taken, &blocks)
 			break
 		case "MAJOR", "MINOR", "SPEC":
-			parseBlock(block, catalogue, &courses, &taken, &enrolled, &blocks)
+			parseBlock(block, goals, catalogue, &courses, &taken, &enrolled, &blocks)
 			break
 		default:
 			break
 		}
 	}
 	
+	byRule := make(map[string][]types.Exception, 0)
+	for _, exception := range exceptions {
+		byRule[exception.Rule] = append(byRule[exception.Rule], exception)
+	}
+	for i := range blocks {
+		applyExceptions(blocks[i].Rules, byRule, transcript, &courses, &taken)
+	}
+	
 	student.Courses = courses
 	student.Taken = taken
 	student.Blocks = blocks
@@ -102,43 +155,424 @@ func Parse(doc *etree.Document, catalogue *types.Catalogue) types.Student {
 	return student
 }
 
+// headerElements finds the elements with the given tag within the audit
+// header and the degree information, leaving out the blocks below them.
+func headerElements(tag string, headers ...*etree.Element) []*etree.Element {
+	elements := make([]*etree.Element, 0)
+	for _, header := range headers {
+		if header != nil {
+			elements = append(elements, header.FindElements(".//" + tag)...)
+		}
+	}
+	return elements
+}
+
+func parseAuditInfo(audit *etree.Element) types.AuditInfo {
+	info := types.AuditInfo{}
+	info.ID = audit.SelectAttrValue("Audit_id", "")
+	info.Type = audit.SelectAttrValue("Audit_type", "")
+	year, errY := strconv.Atoi(audit.SelectAttrValue("Date_YYYY", ""))
+	month, errM := strconv.Atoi(audit.SelectAttrValue("Date_MM", ""))
+	day, errD := strconv.Atoi(audit.SelectAttrValue("Date_DD", ""))
+	if (errY == nil) && (errM == nil) && (errD == nil) {
+		info.Date = fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+	}
+	return info
+}
+
+func parseDegree(degreeData *etree.Element) types.Degree {
+	degree := types.Degree{}
+	degree.Code = strings.ToUpper(degreeData.SelectAttrValue("Degree", ""))
+	degree.Literal = elementValue(degreeData, "Degree_literal", "Degree_lit")
+	degree.School = elementValue(degreeData, "School_literal", "School")
+	degree.Level = elementValue(degreeData, "Stu_level", "Level")
+	degree.CatalogYear = elementValue(degreeData, "Cat_yr", "Catalog_year")
+	degree.ActiveTerm = degreeData.SelectAttrValue("Actv_term", "")
+	return degree
+}
+
+// parseNote reads an audit note, whose text may be split across several
+// `Text` elements.
+func parseNote(n *etree.Element) types.Note {
+	note := types.Note{}
+	note.Type = elementValue(n, "Note_type", "Type")
+	note.Who = elementValue(n, "Note_who", "Who")
+	note.Date = elementValue(n, "Note_date", "Date")
+	lines := make([]string, 0)
+	for _, text := range n.SelectElements("Text") {
+		if line := strings.TrimSpace(text.Text()); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, strings.TrimSpace(n.Text()))
+	}
+	note.Text = strings.Join(lines, "\n")
+	return note
+}
+
+func parseClass(class *etree.Element, previous types.Transcript) types.TranscriptEntry {
+	cDept := class.SelectAttrValue("Discipline", "DEPT")
+	cNum := class.SelectAttrValue("Number", "0")
+	entry := types.TranscriptEntry{}
+	entry.Course = strings.Replace(strings.ToUpper(cDept + cNum), " ", "", -1)
+	entry.Department = strings.ToUpper(strings.TrimSpace(cDept))
+	entry.Number = strings.ToUpper(strings.TrimSpace(cNum))
+	entry.Title = class.SelectAttrValue("Course_title", "")
+	entry.Term = class.SelectAttrValue("Term", "")
+	entry.Grade = strings.TrimSpace(class.SelectAttrValue("Letter_grade", ""))
+	entry.Credits, _ = strconv.ParseFloat(class.SelectAttrValue("Credits", "0.0"), 64)
+	entry.InProgress = class.SelectAttrValue("In_progress", "N") == "Y"
+	
+	// Unrecognized transfer codes are taken as transfer work, which keeps
+	// them out of the GPA rather than counting them as classes taken here.
+	transfer, testCredit, ok := types.TransferCode(class.SelectAttrValue("Transfer", ""))
+	entry.Transfer = transfer || !ok
+	entry.TestCredit = testCredit
+	
+	entry.Repeat = len(strings.TrimSpace(class.SelectAttrValue("Repeat_policy", ""))) > 0
+	for _, e := range previous {
+		if e.Course == entry.Course {
+			entry.Repeat = true
+			break
+		}
+	}
+	
+	// `Gpa_grade_pts` is the total for the class, so it's only used when it
+	// comes with the credits it was earned over and is within an A+ for them.
+	// Zero credits leave the class out of the GPA, as DegreeWorks does for
+	// repeated classes. Otherwise the GPA is worked out from the letter grade.
+	points, errP := strconv.ParseFloat(class.SelectAttrValue("Gpa_grade_pts", ""), 64)
+	credits, errC := strconv.ParseFloat(class.SelectAttrValue("Gpa_credits", ""), 64)
+	maxPoints, _ := types.GradePoints("A+")
+	if (errP == nil) && (errC == nil) && (credits >= 0.0) && (points >= 0.0) && (points <= maxPoints*credits) {
+		entry.GradePoints = points
+		entry.GPACredits = credits
+	} else if points, ok := types.GradePoints(entry.Grade); ok && !entry.InProgress && !entry.Transfer {
+		entry.GradePoints = points * entry.Credits
+		entry.GPACredits = entry.Credits
+	}
+	return entry
+}
+
 func parseProgram(block *etree.Element, catalogue *types.Catalogue, courses *map[string]types.Course, taken *map[string]bool, blocks *[]types.Block) {
 	// TODO: Parse for things like "LOWER DIVISION WRITING" and "UPPER DIVISION WRITING"
 }
 
-func parseBlock(block *etree.Element, catalogue *types.Catalogue, courses *map[string]types.Course, taken *map[string]bool, enrolled *map[string]string, blocks *[]types.Block) {
+func parseBlock(block *etree.Element, goals []types.Goal, catalogue *types.Catalogue, courses *map[string]types.Course, taken *map[string]bool, enrolled *map[string]string, blocks *[]types.Block) {
+	rules, conditionals := parseRules(block, goals, catalogue, courses, taken, enrolled)
+	reqType := block.SelectAttrValue("Req_type", "UNKNOWN")
+	reqValue := strings.ToUpper(block.SelectAttrValue("Req_value", ""))
+	title := block.SelectAttrValue("Title", "Untitled")
+	creditsApplied, _ := strconv.ParseFloat(block.SelectAttrValue("Credits_applied", "0.0"), 64)
+	qualifiers := parseQualifiers(block)
+	inheritQualifiers(rules, qualifiers)
+	theBlock := types.Block{ReqType: reqType, ReqValue: reqValue, Title: title, Rules: rules, Conditionals: conditionals, CreditsApplied: creditsApplied, Qualifiers: qualifiers, Status: parseStatus(block)}
+	*blocks = append(*blocks, theBlock)
+}
+
+func parseRules(parent *etree.Element, goals []types.Goal, catalogue *types.Catalogue, courses *map[string]types.Course, taken *map[string]bool, enrolled *map[string]string) ([]types.Rule, []types.Conditional) {
 	rules := make([]types.Rule, 0)
-	for _, r := range block.SelectElements("Rule") {
-		ruleType := block.SelectAttrValue("RuleType", "")
+	conditionals := make([]types.Conditional, 0)
+	for _, r := range parent.SelectElements("Rule") {
+		ruleType := r.SelectAttrValue("RuleType", "")
 		if ruleType == "IfStmt" {
+			conditional, nested := parseIfStmt(r, goals, catalogue, courses, taken, enrolled)
+			conditionals = append(conditionals, conditional)
+			conditionals = append(conditionals, nested...)
+			rules = append(rules, conditional.Active()...)
 			continue
 		}
-		label := r.SelectAttrValue("Label", "")
-		rs := r.SelectElements("Rule")
-		if len(rs) > 0 {
-			req := r.SelectElement("Requirement")
-			required, _ := strconv.Atoi(req.SelectAttrValue("NumGroups", "0"))
-			rule := types.Rule{Label: label, Required: required}
-			requirements := make([]types.Requirement, 0)
-			for _, r2 := range rs {
-				requirement := parseRule(r2, catalogue, courses, taken, enrolled)
-				requirements = append(requirements, requirement)
-			}
-			rule.Requirements = requirements
-			rules = append(rules, rule)
-		} else {
-			rule := types.Rule{Label: label, Required: 1}
-			requirements := make([]types.Requirement, 0)
-			requirement := parseRule(r, catalogue, courses, taken, enrolled)
-			requirements = append(requirements, requirement)
-			rule.Requirements = requirements
-			rules = append(rules, rule)
+		rule, nested := parseRuleTree(r, goals, catalogue, courses, taken, enrolled)
+		conditionals = append(conditionals, nested...)
+		rules = append(rules, rule)
+	}
+	return rules, conditionals
+}
+
+func parseRuleTree(r *etree.Element, goals []types.Goal, catalogue *types.Catalogue, courses *map[string]types.Course, taken *map[string]bool, enrolled *map[string]string) (types.Rule, []types.Conditional) {
+	label := r.SelectAttrValue("Label", "")
+	kind := r.SelectAttrValue("RuleType", types.CourseRule)
+	rule := types.Rule{ID: r.SelectAttrValue("Node_id", ""), Label: label, Kind: kind, Required: 1, Requirements: make([]types.Requirement, 0), Qualifiers: parseQualifiers(r), Status: parseStatus(r)}
+	conditionals := make([]types.Conditional, 0)
+	req := r.SelectElement("Requirement")
+	if len(r.SelectElements("Rule")) > 0 {
+		if (kind != types.GroupRule) && (kind != types.SubsetRule) {
+			rule.Kind = types.GroupRule
 		}
+		rule.Rules, conditionals = parseRules(r, goals, catalogue, courses, taken, enrolled)
+		if rule.Kind == types.SubsetRule {
+			rule.Required = len(rule.Rules)
+		} else if req != nil {
+			rule.Required, _ = strconv.Atoi(req.SelectAttrValue("NumGroups", "0"))
+		}
+		return rule, conditionals
 	}
-	reqType := block.SelectAttrValue("Req_type", "UNKNOWN")
-	title := block.SelectAttrValue("Title", "Untitled")
-	theBlock := types.Block{ReqType: reqType, Title: title, Rules: rules}
-	*blocks = append(*blocks, theBlock)
+	switch kind {
+	case types.BlockRule, types.BlocktypeRule:
+		if req != nil {
+			rule.BlockType = strings.ToUpper(req.SelectAttrValue("Type", ""))
+			rule.BlockValue = strings.ToUpper(req.SelectAttrValue("Value", ""))
+		}
+	case types.NoncourseRule:
+		// Noncourse rules (e.g. a GPA check or an exit exam) list no classes,
+		// so they're evaluated from the audit's own status alone.
+	default:
+		requirement := parseRule(r, catalogue, courses, taken, enrolled)
+		rule.Requirements = append(rule.Requirements, requirement)
+	}
+	return rule, conditionals
+}
+
+func parseException(e *etree.Element, catalogue *types.Catalogue, courses *map[string]types.Course) types.Exception {
+	exception := types.Exception{}
+	exception.Kind = types.ExceptionKind(e.SelectAttrValue("Type", ""))
+	exception.Label = e.SelectAttrValue("Label", "")
+	exception.Rule = e.SelectAttrValue("Node_id", "")
+	exception.Who = e.SelectAttrValue("Who", "")
+	exception.Date = e.SelectAttrValue("Date", "")
+	exception.Courses = make([]string, 0)
+	for _, course := range e.SelectElements("Course") {
+		c := types.Course{Department: course.SelectAttrValue("Disc", "DEPT"), Number: course.SelectAttrValue("Num", "0")}
+		key := c.Key()
+		if cc, ok := (*catalogue).Courses[key]; ok {
+			c = cc
+		}
+		if _, ok := (*courses)[key]; !ok {
+			(*courses)[key] = c
+		}
+		exception.Courses = append(exception.Courses, key)
+	}
+	return exception
+}
+
+// applyExceptions attaches exceptions to the rules they target, matched by
+// node ID. Substituted and applied courses the student has completed count
+// toward the rule, credits included, while Also Allow exceptions only add
+// options.
+func applyExceptions(rules []types.Rule, byRule map[string][]types.Exception, transcript types.Transcript, courses *map[string]types.Course, taken *map[string]bool) {
+	for i := range rules {
+		rule := &rules[i]
+		applyExceptions(rule.Rules, byRule, transcript, courses, taken)
+		if len(rule.ID) == 0 {
+			continue
+		}
+		for _, exception := range byRule[rule.ID] {
+			rule.Exceptions = append(rule.Exceptions, exception)
+			if exception.Kind == types.WaiveException {
+				continue
+			}
+			for _, key := range exception.Courses {
+				req := exceptionTarget(rule, (*courses)[key])
+				if req == nil {
+					continue
+				}
+				req.Options = appendOption(req.Options, key)
+				if exception.Kind == types.AlsoAllowException {
+					continue
+				}
+				if ((*taken)[key] || transcript.Completed(key)) && !contains(req.Completed, key) {
+					(*taken)[key] = true
+					req.Completed = append(req.Completed, key)
+					if credits := transcript.Credits(key); credits > 0.0 {
+						req.CreditsApplied += credits
+					} else {
+						req.CreditsApplied += (*courses)[key].Units
+					}
+				}
+			}
+		}
+	}
+}
+
+// exceptionTarget returns the requirement an exception's course applies to.
+// Group rules hold no requirements of their own, so the first one beneath
+// the rule that accepts the course is used, or else the first one at all.
+func exceptionTarget(rule *types.Rule, course types.Course) *types.Requirement {
+	var first *types.Requirement
+	var find func(r *types.Rule) *types.Requirement
+	find = func(r *types.Rule) *types.Requirement {
+		for i := range r.Requirements {
+			req := &r.Requirements[i]
+			if first == nil {
+				first = req
+			}
+			if req.Accepts(course) {
+				return req
+			}
+		}
+		for i := range r.Rules {
+			if req := find(&r.Rules[i]); req != nil {
+				return req
+			}
+		}
+		return nil
+	}
+	if req := find(rule); req != nil {
+		return req
+	}
+	return first
+}
+
+func contains(slice []string, s string) bool {
+	for _, e := range slice {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func parseStatus(element *etree.Element) types.Status {
+	status := types.Status{}
+	if percent, err := strconv.ParseFloat(element.SelectAttrValue("Per_complete", ""), 64); err == nil {
+		status.Reported = true
+		status.PercentComplete = percent
+	}
+	status.InProgress = strings.ToUpper(element.SelectAttrValue("In_progress", "N")) == "Y"
+	return status
+}
+
+// parseQualifiers reads the `Qualifier` elements of a rule or block. Values
+// are taken from an attribute or a child element of the same name, so both
+// `<Qualifier Name="MINGRADE" Grade="C"/>` and
+// `<Qualifier Name="MINGRADE"><Grade>C</Grade></Qualifier>` are understood.
+func parseQualifiers(element *etree.Element) types.Qualifiers {
+	qualifiers := types.Qualifiers{}
+	for _, q := range element.SelectElements("Qualifier") {
+		name := strings.Replace(strings.ToUpper(q.SelectAttrValue("Name", "")), "_", "", -1)
+		switch name {
+		case "MINGRADE":
+			qualifiers.MinGrade = strings.ToUpper(elementValue(q, "Grade", "Mingrade", "Value"))
+		case "MINGPA":
+			qualifiers.MinGPA, _ = strconv.ParseFloat(elementValue(q, "Gpa", "Mingpa", "Value"), 64)
+		case "MAXPASSFAIL":
+			if classes, err := strconv.Atoi(elementValue(q, "Classes", "Maxpassfail", "Value")); err == nil {
+				qualifiers.MaxPassfail = &classes
+			}
+		case "MAXCLASSES":
+			if classes, err := strconv.Atoi(elementValue(q, "Classes", "Maxclasses", "Value")); err == nil {
+				qualifiers.MaxClasses = &classes
+			}
+		case "MAXCREDITS":
+			if credits, err := strconv.ParseFloat(elementValue(q, "Credits", "Maxcredits", "Value"), 64); err == nil {
+				qualifiers.MaxCredits = &credits
+			}
+		case "NONEXCLUSIVE":
+			qualifiers.NonExclusive = true
+		case "SHARE", "SHAREWITH":
+			for _, child := range q.ChildElements() {
+				if value := strings.TrimSpace(child.Text()); len(value) > 0 {
+					qualifiers.ShareWith = append(qualifiers.ShareWith, strings.ToUpper(value))
+				}
+			}
+			for _, value := range strings.Split(q.SelectAttrValue("Value", ""), ",") {
+				if value = strings.TrimSpace(value); len(value) > 0 {
+					qualifiers.ShareWith = append(qualifiers.ShareWith, strings.ToUpper(value))
+				}
+			}
+		}
+	}
+	return qualifiers
+}
+
+func elementValue(element *etree.Element, names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(element.SelectAttrValue(name, "")); len(value) > 0 {
+			return value
+		}
+		if child := element.SelectElement(name); child != nil {
+			return strings.TrimSpace(child.Text())
+		}
+	}
+	return ""
+}
+
+// inheritQualifiers copies a minimum grade down to the rules beneath it that
+// don't set their own, as DegreeWorks applies it to every class in scope.
+// The GPA minimum and the pass/no pass, class and unit limits apply to the
+// classes in scope as a whole, so they stay where they're declared and are
+// applied there to everything beneath them.
+func inheritQualifiers(rules []types.Rule, parent types.Qualifiers) {
+	for i := range rules {
+		if len(rules[i].Qualifiers.MinGrade) == 0 {
+			rules[i].Qualifiers.MinGrade = parent.MinGrade
+		}
+		inheritQualifiers(rules[i].Rules, rules[i].Qualifiers)
+	}
+}
+
+// parseIfStmt reads both branches of an IfStmt rule, along with the
+// conditionals nested in the branch that applies. The other branch is parsed
+// into copies of the course maps, so its classes don't count as taken.
+func parseIfStmt(rule *etree.Element, goals []types.Goal, catalogue *types.Catalogue, courses *map[string]types.Course, taken *map[string]bool, enrolled *map[string]string) (types.Conditional, []types.Conditional) {
+	conditional := types.Conditional{Label: rule.SelectAttrValue("Label", "")}
+	conditional.Then = make([]types.Rule, 0)
+	conditional.Else = make([]types.Rule, 0)
+	req := rule.SelectElement("Requirement")
+	if req == nil {
+		return conditional, make([]types.Conditional, 0)
+	}
+	if condition, ok := parseCondition(req); ok {
+		conditional.Condition = condition
+		conditional.Satisfied = condition.Evaluate(goals)
+	}
+	
+	parseBranch := func(part *etree.Element, active bool) ([]types.Rule, []types.Conditional) {
+		if part == nil {
+			return make([]types.Rule, 0), make([]types.Conditional, 0)
+		}
+		if active {
+			return parseRules(part, goals, catalogue, courses, taken, enrolled)
+		}
+		scratchCourses := make(map[string]types.Course, len(*courses))
+		for key, course := range *courses {
+			scratchCourses[key] = course
+		}
+		scratchTaken := make(map[string]bool, len(*taken))
+		for key, t := range *taken {
+			scratchTaken[key] = t
+		}
+		return parseRules(part, goals, catalogue, &scratchCourses, &scratchTaken, enrolled)
+	}
+	var thenNested, elseNested []types.Conditional
+	conditional.Then, thenNested = parseBranch(req.SelectElement("IfPart"), conditional.Satisfied)
+	conditional.Else, elseNested = parseBranch(req.SelectElement("ElsePart"), !conditional.Satisfied)
+	if conditional.Satisfied {
+		return conditional, thenNested
+	}
+	return conditional, elseNested
+}
+
+// parseCondition reads the condition tree of an IfStmt. Relations carry their
+// `Left`, `Operator` and `Right` attributes, and `LeftCondition`/
+// `RightCondition` pairs are joined by the `Connector` of their parent.
+func parseCondition(element *etree.Element) (types.Condition, bool) {
+	if element.Tag == "Relation" {
+		condition := types.Condition{
+			Left:     strings.ToUpper(strings.TrimSpace(element.SelectAttrValue("Left", ""))),
+			Operator: strings.TrimSpace(element.SelectAttrValue("Operator", "=")),
+			Right:    strings.ToUpper(strings.TrimSpace(element.SelectAttrValue("Right", ""))),
+		}
+		return condition, len(condition.Left) > 0
+	}
+	conditions := make([]types.Condition, 0)
+	for _, child := range element.ChildElements() {
+		switch child.Tag {
+		case "Relation", "LeftCondition", "RightCondition":
+			if c, ok := parseCondition(child); ok {
+				conditions = append(conditions, c)
+			}
+		}
+	}
+	switch len(conditions) {
+	case 0:
+		return types.Condition{}, false
+	case 1:
+		return conditions[0], true
+	}
+	connector := strings.ToUpper(element.SelectAttrValue("Connector", "AND"))
+	return types.Condition{Connector: connector, Conditions: conditions}, true
 }
 
 func parseRule(rule *etree.Element, catalogue *types.Catalogue, courses *map[string]types.Course, taken *map[string]bool, enrolled *map[string]string) types.Requirement {
@@ -151,28 +585,65 @@ func parseRule(rule *etree.Element, catalogue *types.Catalogue, courses *map[str
 	if requirementBlock != nil {
 		required, _ := strconv.Atoi(requirementBlock.SelectAttrValue("Classes_begin", "0"))
 		requirement.Required = required
+		credits, _ := strconv.ParseFloat(requirementBlock.SelectAttrValue("Credits_begin", "0.0"), 64)
+		requirement.Credits = credits
+		requirement.Connector = strings.ToUpper(requirementBlock.SelectAttrValue("Class_cred_op", ""))
+		except := make([]types.CoursePattern, 0)
+		for _, e := range requirementBlock.SelectElements("Except") {
+			for _, course := range e.SelectElements("Course") {
+				except = append(except, types.NewCoursePattern(course.SelectAttrValue("Disc", "@"), course.SelectAttrValue("Num", "@")))
+			}
+		}
+		patterns := make([]types.CoursePattern, 0)
 		for _, course := range requirementBlock.SelectElements("Course") {
 			cDept := course.SelectAttrValue("Disc", "DEPT")
 			cNum := course.SelectAttrValue("Num", "0")
 			
+			pattern := types.NewCoursePattern(cDept, cNum)
+			if !pattern.IsLiteral() {
+				// Patterns over every department (`@ @`, `@ 1@`) would list
+				// most of the catalogue, so they're only matched lazily.
+				patterns = append(patterns, pattern)
+				if pattern.Department != "@" {
+					for _, key := range (*catalogue).Expand(pattern, except) {
+						if _, ok := (*courses)[key]; !ok {
+							(*courses)[key] = (*catalogue).Courses[key]
+						}
+						options = appendOption(options, key)
+					}
+				}
+				continue
+			}
+			
+			// Courses already recorded, e.g. with the grade of an earlier
+			// rule's applied class, are kept as they are.
 			key := strings.Replace(strings.ToUpper(cDept + cNum), " ", "", -1)
-			if c, ok := (*catalogue).Courses[key]; ok {
-				(*courses)[key] = c
-			} else {
-				c := types.Course{Department: cDept, Number: cNum}
+			if _, ok := (*courses)[key]; !ok {
+				c, ok := (*catalogue).Courses[key]
+				if !ok {
+					c = types.Course{Department: cDept, Number: cNum}
+				}
 				(*courses)[key] = c
 			}
-			options = append(options, key)
+			options = appendOption(options, key)
+		}
+		if len(patterns) > 0 {
+			requirement.Patterns = patterns
+			if len(except) > 0 {
+				requirement.Except = except
+			}
 		}
 	}
 	
 	// Taken Classes
+	creditsApplied := 0.0
 	applied := rule.SelectElement("ClassesApplied")
 	if applied != nil {
 		for _, course := range applied.SelectElements("Class") {
 			cDept := course.SelectAttrValue("Discipline", "DEPT")
 			cNum := course.SelectAttrValue("Number", "0")
 			cGrade := course.SelectAttrValue("Letter_grade", "")
+			cCredits, _ := strconv.ParseFloat(course.SelectAttrValue("Credits", "0.0"), 64)
 			
 			c := types.Course{Department: cDept, Number: cNum}
 			key := c.Key()
@@ -186,6 +657,7 @@ func parseRule(rule *etree.Element, catalogue *types.Catalogue, courses *map[str
 				c.Grade = cGrade
 				(*taken)[key] = true
 				completed = append(completed, key)
+				creditsApplied += cCredits
 			} else if cTitle, ok := (*enrolled)[key]; ok {
 				if len(c.ShortTitle) == 0 {
 					c.ShortTitle = cTitle
@@ -193,12 +665,29 @@ func parseRule(rule *etree.Element, catalogue *types.Catalogue, courses *map[str
 			} else {
 				(*taken)[key] = true
 				completed = append(completed, key)
+				creditsApplied += cCredits
 			}
 			(*courses)[key] = c
 		}
 	}
 	
+	// The audit's own total takes precedence, as it accounts for classes
+	// that only count partially.
+	if credits, err := strconv.ParseFloat(rule.SelectAttrValue("Credits_applied", ""), 64); err == nil {
+		creditsApplied = credits
+	}
+	
 	requirement.Options = options
 	requirement.Completed = completed
+	requirement.CreditsApplied = creditsApplied
 	return requirement
 }
+
+func appendOption(options []string, key string) []string {
+	for _, option := range options {
+		if option == key {
+			return options
+		}
+	}
+	return append(options, key)
+}
@@ -0,0 +1,370 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package parsers
+
+import (
+	"github.com/beevik/etree"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func parseTestBlock(t *testing.T, xml string, goals []types.Goal) (types.Block, map[string]types.Course, map[string]bool) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(xml); err != nil {
+		t.Fatal(err)
+	}
+	catalogue := types.Catalogue{Courses: map[string]types.Course{}}
+	courses := make(map[string]types.Course, 0)
+	taken := make(map[string]bool, 0)
+	enrolled := make(map[string]string, 0)
+	blocks := make([]types.Block, 0)
+	parseBlock(doc.Root(), goals, &catalogue, &courses, &taken, &enrolled, &blocks)
+	return blocks[0], courses, taken
+}
+
+const ifStmtBlock = `<Block Req_type="MAJOR" Req_value="201" Title="Major">
+	<Rule RuleType="IfStmt" Label="Computer Science">
+		<Requirement>
+			<Relation Left="MAJOR" Operator="=" Right="201"/>
+			<IfPart>
+				<Rule RuleType="Course" Label="Algorithms">
+					<Requirement Classes_begin="1"><Course Disc="COMPSCI" Num="161"/></Requirement>
+					<ClassesApplied><Class Discipline="COMPSCI" Number="161" Letter_grade="A" Credits="4"/></ClassesApplied>
+				</Rule>
+				<Rule RuleType="IfStmt" Label="Intelligent Systems">
+					<Requirement>
+						<Relation Left="SPEC" Operator="=" Right="AI"/>
+						<IfPart>
+							<Rule RuleType="Course" Label="Artificial Intelligence">
+								<Requirement Classes_begin="1"><Course Disc="COMPSCI" Num="171"/></Requirement>
+							</Rule>
+						</IfPart>
+					</Requirement>
+				</Rule>
+			</IfPart>
+			<ElsePart>
+				<Rule RuleType="Course" Label="Calculus">
+					<Requirement Classes_begin="1"><Course Disc="MATH" Num="2A"/></Requirement>
+					<ClassesApplied><Class Discipline="MATH" Number="2A" Letter_grade="B" Credits="4"/></ClassesApplied>
+				</Rule>
+			</ElsePart>
+		</Requirement>
+	</Rule>
+</Block>`
+
+func TestParseIfStmt(t *testing.T) {
+	tests := []struct {
+		name         string
+		goals        []types.Goal
+		rules        []string
+		conditionals []string
+		taken        []string
+		untouched    []string
+	}{
+		{"both conditions hold", []types.Goal{{Code: "MAJOR", Value: "201"}, {Code: "SPEC", Value: "AI"}}, []string{"Algorithms", "Artificial Intelligence"}, []string{"Computer Science", "Intelligent Systems"}, []string{"COMPSCI161"}, []string{"MATH2A"}},
+		{"nested condition fails", []types.Goal{{Code: "MAJOR", Value: "201"}}, []string{"Algorithms"}, []string{"Computer Science", "Intelligent Systems"}, []string{"COMPSCI161"}, []string{"MATH2A"}},
+		{"outer condition fails", []types.Goal{{Code: "MAJOR", Value: "100"}}, []string{"Calculus"}, []string{"Computer Science"}, []string{"MATH2A"}, []string{"COMPSCI161", "COMPSCI171"}},
+	}
+	for _, test := range tests {
+		block, courses, taken := parseTestBlock(t, ifStmtBlock, test.goals)
+		rules := make([]string, 0)
+		for _, rule := range block.Rules {
+			rules = append(rules, rule.Label)
+		}
+		if !reflect.DeepEqual(rules, test.rules) {
+			t.Errorf("%v: rules = %v, want %v", test.name, rules, test.rules)
+		}
+		conditionals := make([]string, 0)
+		for _, conditional := range block.Conditionals {
+			conditionals = append(conditionals, conditional.Label)
+		}
+		if !reflect.DeepEqual(conditionals, test.conditionals) {
+			t.Errorf("%v: conditionals = %v, want %v", test.name, conditionals, test.conditionals)
+		}
+		for _, key := range test.taken {
+			if !taken[key] {
+				t.Errorf("%v: %v not taken", test.name, key)
+			}
+		}
+		for _, key := range test.untouched {
+			if _, ok := courses[key]; ok || taken[key] {
+				t.Errorf("%v: %v from the inactive branch was recorded", test.name, key)
+			}
+		}
+	}
+}
+
+func TestParseNoncourseRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		completed bool
+	}{
+		{"unreported", ``, false},
+		{"incomplete", ` Per_complete="0"`, false},
+		{"in progress", ` Per_complete="50" In_progress="Y"`, false},
+		{"complete", ` Per_complete="100"`, true},
+	}
+	for _, test := range tests {
+		xml := `<Block Req_type="DEGREE" Title="Degree"><Rule RuleType="Noncourse" Label="Exit Exam"` + test.status + `><Requirement><Noncourse Code="EXIT"/></Requirement></Rule></Block>`
+		block, _, _ := parseTestBlock(t, xml, []types.Goal{})
+		student := types.Student{Courses: map[string]types.Course{}, Taken: map[string]bool{}, Blocks: []types.Block{block}}
+		if len(block.Rules[0].Requirements) != 0 {
+			t.Errorf("%v: parsed requirements %v", test.name, block.Rules[0].Requirements)
+		}
+		if completed := block.Rules[0].IsCompleted(&student); completed != test.completed {
+			t.Errorf("%v: IsCompleted = %v, want %v", test.name, completed, test.completed)
+		}
+	}
+}
+
+func TestParseKeepsAppliedGrades(t *testing.T) {
+	tests := []struct {
+		name   string
+		option string
+	}{
+		{"literal option", `<Course Disc="COMPSCI" Num="161"/>`},
+		{"wildcard option", `<Course Disc="COMPSCI" Num="1@"/>`},
+	}
+	for _, test := range tests {
+		xml := `<Block Req_type="MAJOR" Title="Major">
+			<Qualifier Name="MINGRADE" Grade="C"/>
+			<Rule RuleType="Course" Label="Algorithms">
+				<Requirement Classes_begin="1"><Course Disc="COMPSCI" Num="161"/></Requirement>
+				<ClassesApplied><Class Discipline="COMPSCI" Number="161" Letter_grade="D" Credits="4"/></ClassesApplied>
+			</Rule>
+			<Rule RuleType="Course" Label="Elective">
+				<Requirement Classes_begin="1">` + test.option + `</Requirement>
+			</Rule>
+		</Block>`
+		doc := etree.NewDocument()
+		if err := doc.ReadFromString(xml); err != nil {
+			t.Fatal(err)
+		}
+		catalogue := types.Catalogue{Courses: map[string]types.Course{"COMPSCI161": {Department: "COMPSCI", Number: "161", Units: 4.0}}}
+		courses := make(map[string]types.Course, 0)
+		taken := make(map[string]bool, 0)
+		blocks := make([]types.Block, 0)
+		parseBlock(doc.Root(), []types.Goal{}, &catalogue, &courses, &taken, &map[string]string{}, &blocks)
+		student := types.Student{Courses: courses, Taken: taken, Blocks: blocks}
+		
+		if grade := courses["COMPSCI161"].Grade; grade != "D" {
+			t.Errorf("%v: grade = %q, want D", test.name, grade)
+		}
+		rule := blocks[0].Rules[0]
+		if rule.IsCompleted(&student) {
+			t.Errorf("%v: a D completed a rule with a minimum grade of C", test.name)
+		}
+		if _, messages := rule.Check(&student); len(messages) != 1 {
+			t.Errorf("%v: Check messages = %v, want one", test.name, messages)
+		}
+	}
+}
+
+func TestApplyExceptions(t *testing.T) {
+	course := func(number string) types.Rule {
+		key := "COMPSCI" + number
+		return types.Rule{ID: "R" + number, Label: key, Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{{Required: 1, Options: []string{key}, Completed: []string{}}}}
+	}
+	group := func() types.Rule {
+		return types.Rule{ID: "G", Label: "Electives", Kind: types.GroupRule, Required: 1, Rules: []types.Rule{course("171"), course("178")}}
+	}
+	transcript := types.Transcript{
+		{Course: "COMPSCI175", Department: "COMPSCI", Number: "175", Credits: 4.0},
+		{Course: "COMPSCI178", Department: "COMPSCI", Number: "178", Credits: 4.0},
+	}
+	tests := []struct {
+		name      string
+		rule      types.Rule
+		exception types.Exception
+		path      []int
+		options   []string
+		completed []string
+		credits   float64
+	}{
+		{"substitute", course("161"), types.Exception{Kind: types.SubstituteException, Rule: "R161", Courses: []string{"COMPSCI175"}}, []int{}, []string{"COMPSCI161", "COMPSCI175"}, []string{"COMPSCI175"}, 4.0},
+		{"substitute not yet taken", course("161"), types.Exception{Kind: types.SubstituteException, Rule: "R161", Courses: []string{"COMPSCI162"}}, []int{}, []string{"COMPSCI161", "COMPSCI162"}, []string{}, 0.0},
+		{"also allow", course("161"), types.Exception{Kind: types.AlsoAllowException, Rule: "R161", Courses: []string{"COMPSCI175"}}, []int{}, []string{"COMPSCI161", "COMPSCI175"}, []string{}, 0.0},
+		{"waive", course("161"), types.Exception{Kind: types.WaiveException, Rule: "R161"}, []int{}, []string{"COMPSCI161"}, []string{}, 0.0},
+		{"group, to the child listing the course", group(), types.Exception{Kind: types.ApplyHereException, Rule: "G", Courses: []string{"COMPSCI178"}}, []int{1}, []string{"COMPSCI178"}, []string{"COMPSCI178"}, 4.0},
+		{"group, to the first child", group(), types.Exception{Kind: types.SubstituteException, Rule: "G", Courses: []string{"COMPSCI175"}}, []int{0}, []string{"COMPSCI171", "COMPSCI175"}, []string{"COMPSCI175"}, 4.0},
+	}
+	for _, test := range tests {
+		rules := []types.Rule{test.rule}
+		courses := map[string]types.Course{"COMPSCI175": {Department: "COMPSCI", Number: "175", Units: 4.0}, "COMPSCI178": {Department: "COMPSCI", Number: "178", Units: 4.0}}
+		taken := make(map[string]bool, 0)
+		applyExceptions(rules, map[string][]types.Exception{test.exception.Rule: {test.exception}}, transcript, &courses, &taken)
+		
+		if len(rules[0].Exceptions) != 1 {
+			t.Errorf("%v: exceptions = %v, want one", test.name, rules[0].Exceptions)
+		}
+		target := &rules[0]
+		for _, i := range test.path {
+			target = &target.Rules[i]
+		}
+		req := target.Requirements[0]
+		if !reflect.DeepEqual(req.Options, test.options) {
+			t.Errorf("%v: options = %v, want %v", test.name, req.Options, test.options)
+		}
+		if !reflect.DeepEqual(req.Completed, test.completed) {
+			t.Errorf("%v: completed = %v, want %v", test.name, req.Completed, test.completed)
+		}
+		if req.CreditsApplied != test.credits {
+			t.Errorf("%v: CreditsApplied = %v, want %v", test.name, req.CreditsApplied, test.credits)
+		}
+	}
+}
+
+const headerReport = `<Report>
+	<Audit>
+		<AuditHeader Stu_id="12345678" Stu_name="ANTEATER, PETER" Stu_email="PETER@UCI.EDU" Audit_id="A0001" Audit_type="AA" Date_YYYY="2018" Date_MM="3" Date_DD="9">
+			<Note Note_type="ADV" Note_who="Advisor" Note_date="2018-03-01"><Text>Meet before</Text><Text>enrolling.</Text></Note>
+			<Hold Hold_code="REG" Hold_desc="Registration hold"/>
+		</AuditHeader>
+		<Deginfo>
+			<DegreeData Degree="BS" Degree_literal="Bachelor of Science" Cat_yr="2017" Actv_term="201814"/>
+			<Goal Code="MAJOR" Value="201" Value_literal="Computer Science"/>
+			<Goal Code="MINOR" Value="M1" Value_literal="Mathematics"/>
+			<Advisor Advisor_name="Zot, Peter" Advisor_email="ZOT@UCI.EDU" Advisor_type="MAJOR"/>
+		</Deginfo>
+		<Block Req_type="MAJOR" Req_value="201" Title="Major">
+			<Rule RuleType="IfStmt" Label="Catalog 2017 onward">
+				<Requirement>
+					<Relation Left="CATYR" Operator=">=" Right="2017"/>
+					<IfPart>
+						<Rule RuleType="Course" Label="Algorithms">
+							<Requirement Classes_begin="1"><Course Disc="COMPSCI" Num="161"/></Requirement>
+							<Advice><Note Note_type="RULE"><Text>Take it in Fall.</Text></Note></Advice>
+						</Rule>
+					</IfPart>
+					<ElsePart>
+						<Rule RuleType="Course" Label="Calculus">
+							<Requirement Classes_begin="1"><Course Disc="MATH" Num="2A"/></Requirement>
+						</Rule>
+					</ElsePart>
+				</Requirement>
+			</Rule>
+			<Hold Hold_code="RULE" Hold_desc="Not a student hold"/>
+			<Advisor Advisor_name="Not an advisor"/>
+		</Block>
+	</Audit>
+</Report>`
+
+func TestParseHeader(t *testing.T) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromString(headerReport); err != nil {
+		t.Fatal(err)
+	}
+	catalogue := types.Catalogue{Courses: map[string]types.Course{}}
+	student := Parse(doc, &catalogue)
+	
+	if (student.StudentID != "12345678") || (student.Name != "ANTEATER, PETER") || (student.Email != "PETER@UCI.EDU") {
+		t.Errorf("student = %v, %v, %v", student.StudentID, student.Name, student.Email)
+	}
+	if expected := (types.AuditInfo{ID: "A0001", Type: "AA", Date: "2018-03-09"}); student.Audit != expected {
+		t.Errorf("Audit = %+v, want %+v", student.Audit, expected)
+	}
+	if (student.Degree.Code != "BS") || (student.CatalogYear() != "2017") {
+		t.Errorf("Degree = %+v, catalog year %v", student.Degree, student.CatalogYear())
+	}
+	if majors := student.Majors(); (len(majors) != 1) || (majors[0].String() != "Computer Science") {
+		t.Errorf("Majors = %v", majors)
+	}
+	if minors := student.Minors(); (len(minors) != 1) || (minors[0].String() != "Mathematics") {
+		t.Errorf("Minors = %v", minors)
+	}
+	if expected := []types.Advisor{{Name: "Zot, Peter", Email: "ZOT@UCI.EDU", Type: "MAJOR"}}; !reflect.DeepEqual(student.Advisors, expected) {
+		t.Errorf("Advisors = %+v, want %+v", student.Advisors, expected)
+	}
+	if expected := []types.Note{{Type: "ADV", Who: "Advisor", Date: "2018-03-01", Text: "Meet before\nenrolling."}}; !reflect.DeepEqual(student.Notes, expected) {
+		t.Errorf("Notes = %+v, want %+v", student.Notes, expected)
+	}
+	if expected := []types.Hold{{Code: "REG", Description: "Registration hold"}}; !reflect.DeepEqual(student.Holds, expected) {
+		t.Errorf("Holds = %+v, want %+v", student.Holds, expected)
+	}
+	// The catalog year picks the branch that applies.
+	if (len(student.Blocks) != 1) || (len(student.Blocks[0].Rules) != 1) || (student.Blocks[0].Rules[0].Label != "Algorithms") {
+		t.Errorf("Blocks = %+v, want the Algorithms rule of the 2017 catalog", student.Blocks)
+	}
+}
+
+func TestParseClass(t *testing.T) {
+	tests := []struct {
+		name        string
+		class       string
+		transfer    bool
+		testCredit  bool
+		repeat      bool
+		gradePoints float64
+		gpaCredits  float64
+	}{
+		{"letter grade", `<Class Discipline="COMPSCI" Number="161" Term="2017-92" Letter_grade="B+" Credits="4"/>`, false, false, false, 13.2, 4.0},
+		{"reported grade points", `<Class Discipline="COMPSCI" Number="161" Term="2017-92" Letter_grade="B+" Credits="4" Gpa_grade_pts="13.2" Gpa_credits="4"/>`, false, false, false, 13.2, 4.0},
+		{"grade points above an A+", `<Class Discipline="COMPSCI" Number="161" Term="2017-92" Letter_grade="B" Credits="4" Gpa_grade_pts="20" Gpa_credits="4"/>`, false, false, false, 12.0, 4.0},
+		{"grade points without credits", `<Class Discipline="COMPSCI" Number="161" Term="2017-92" Letter_grade="B" Credits="4" Gpa_grade_pts="12"/>`, false, false, false, 12.0, 4.0},
+		{"left out of the GPA", `<Class Discipline="COMPSCI" Number="161" Term="2017-92" Letter_grade="D" Credits="4" Gpa_grade_pts="0" Gpa_credits="0"/>`, false, false, false, 0.0, 0.0},
+		{"pass/no pass", `<Class Discipline="COMPSCI" Number="161" Term="2017-92" Letter_grade="P" Credits="4"/>`, false, false, false, 0.0, 0.0},
+		{"in progress", `<Class Discipline="COMPSCI" Number="161" Term="2018-03" Letter_grade="IP" Credits="4" In_progress="Y"/>`, false, false, false, 0.0, 0.0},
+		{"transfer work", `<Class Discipline="MATH" Number="2A" Term="2016-92" Letter_grade="A" Credits="4" Transfer="T"/>`, true, false, false, 0.0, 0.0},
+		{"exam credit", `<Class Discipline="MATH" Number="2A" Term="2016-92" Letter_grade="A" Credits="4" Transfer="E"/>`, true, true, false, 0.0, 0.0},
+		{"not transferred", `<Class Discipline="MATH" Number="2A" Term="2016-92" Letter_grade="A" Credits="4" Transfer="N"/>`, false, false, false, 16.0, 4.0},
+		{"unknown transfer code", `<Class Discipline="MATH" Number="2A" Term="2016-92" Letter_grade="A" Credits="4" Transfer="Q"/>`, true, false, false, 0.0, 0.0},
+		{"repeat policy", `<Class Discipline="COMPSCI" Number="171" Term="2017-92" Letter_grade="A" Credits="4" Repeat_policy="RP"/>`, false, false, true, 16.0, 4.0},
+		{"repeated enrollment", `<Class Discipline="COMPSCI" Number="122A" Term="2018-03" Letter_grade="A" Credits="4"/>`, false, false, true, 16.0, 4.0},
+	}
+	previous := types.Transcript{{Course: "COMPSCI122A", Term: "2017-92", Grade: "D", Credits: 4.0}}
+	for _, test := range tests {
+		doc := etree.NewDocument()
+		if err := doc.ReadFromString(test.class); err != nil {
+			t.Fatal(err)
+		}
+		entry := parseClass(doc.Root(), previous)
+		if (entry.Transfer != test.transfer) || (entry.TestCredit != test.testCredit) || (entry.Repeat != test.repeat) {
+			t.Errorf("%v: Transfer, TestCredit, Repeat = %v, %v, %v, want %v, %v, %v", test.name, entry.Transfer, entry.TestCredit, entry.Repeat, test.transfer, test.testCredit, test.repeat)
+		}
+		if (math.Abs(entry.GradePoints - test.gradePoints) > 1e-9) || (entry.GPACredits != test.gpaCredits) {
+			t.Errorf("%v: GradePoints, GPACredits = %v, %v, want %v, %v", test.name, entry.GradePoints, entry.GPACredits, test.gradePoints, test.gpaCredits)
+		}
+	}
+}
+
+func TestParseTranscriptGPA(t *testing.T) {
+	classes := []string{
+		`<Class Discipline="COMPSCI" Number="161" Term="2017-92" Letter_grade="A" Credits="4"/>`,
+		`<Class Discipline="COMPSCI" Number="171" Term="2017-92" Letter_grade="C" Credits="2"/>`,
+		`<Class Discipline="COMPSCI" Number="178" Term="2018-03" Letter_grade="P" Credits="4"/>`,
+		`<Class Discipline="MATH" Number="2A" Term="2016-92" Letter_grade="F" Credits="4" Transfer="T"/>`,
+	}
+	transcript := make(types.Transcript, 0)
+	for _, class := range classes {
+		doc := etree.NewDocument()
+		if err := doc.ReadFromString(class); err != nil {
+			t.Fatal(err)
+		}
+		transcript = append(transcript, parseClass(doc.Root(), transcript))
+	}
+	// (4.0 * 4 + 2.0 * 2) / 6 units with letter grades taken here.
+	if gpa, expected := transcript.GPA(), 20.0/6.0; math.Abs(gpa - expected) > 1e-9 {
+		t.Errorf("GPA = %v, want %v", gpa, expected)
+	}
+}
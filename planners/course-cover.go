@@ -0,0 +1,285 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package planners
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"sort"
+	"strings"
+)
+
+/* Minimum Remaining Course Set */
+
+// Instances with at most this many candidate courses are solved exactly.
+const exactCoverCandidates = 30
+
+// exactCoverBudget bounds the number of search nodes, across every way of
+// completing the rules, before the exact solver gives up and the greedy
+// heuristic is used instead.
+const exactCoverBudget = 500000
+
+// maxCoverChoices caps how many combinations of the rules' alternatives are
+// searched exactly.
+const maxCoverChoices = 256
+
+// MaxAlternatives is the number of optimal course sets returned.
+const MaxAlternatives = 5
+
+// CoverResult holds the smallest course sets found. Exact is set only when
+// they are optimal over every way of completing the rules, not just the
+// requirements OutstandingNeeds picks.
+type CoverResult struct {
+	Size        int        `json:"size"`
+	Exact       bool       `json:"exact"`
+	Sets        [][]string `json:"sets"`
+	Uncoverable []string   `json:"uncoverable"`
+}
+
+type cover struct {
+	needs      []Need
+	deficits   []int
+	coveredBy  map[string][]int
+	candidates []string
+}
+
+func newCover(needs []Need) *cover {
+	c := &cover{needs: needs, deficits: make([]int, len(needs)), coveredBy: make(map[string][]int, 0), candidates: make([]string, 0)}
+	for i, need := range needs {
+		c.deficits[i] = need.Remaining
+		if len(need.Options) < need.Remaining {
+			c.deficits[i] = len(need.Options)
+		}
+		for _, option := range need.Options {
+			if _, ok := c.coveredBy[option]; !ok {
+				c.candidates = append(c.candidates, option)
+			}
+			c.coveredBy[option] = append(c.coveredBy[option], i)
+		}
+	}
+	sort.Strings(c.candidates)
+	return c
+}
+
+func (c *cover) take(key string, delta int) {
+	for _, i := range c.coveredBy[key] {
+		c.deficits[i] -= delta
+	}
+}
+
+// gain counts the outstanding deficits a course would reduce.
+func (c *cover) gain(key string) int {
+	gain := 0
+	for _, i := range c.coveredBy[key] {
+		if c.deficits[i] > 0 {
+			gain++
+		}
+	}
+	return gain
+}
+
+func (c *cover) greedy() []string {
+	chosen := make([]string, 0)
+	taken := make(map[string]bool, 0)
+	for {
+		best := ""
+		for _, key := range c.candidates {
+			if !taken[key] && (c.gain(key) > 0) && ((len(best) == 0) || (c.gain(key) > c.gain(best))) {
+				best = key
+			}
+		}
+		if len(best) == 0 {
+			break
+		}
+		taken[best] = true
+		c.take(best, 1)
+		chosen = append(chosen, best)
+	}
+	for _, key := range chosen {
+		c.take(key, -1)
+	}
+	sort.Strings(chosen)
+	return chosen
+}
+
+// exact runs an iterative-deepening search, branching on the options of the
+// need with the largest deficit, and returns every distinct optimal set found
+// (up to MaxAlternatives). It reports false when the shared node budget runs
+// out.
+func (c *cover) exact(nodes *int) ([][]string, bool) {
+	lowerBound := 0
+	for _, d := range c.deficits {
+		if d > lowerBound {
+			lowerBound = d
+		}
+	}
+	for limit := lowerBound; limit <= len(c.candidates); limit++ {
+		sets := make([][]string, 0)
+		seen := make(map[string]bool, 0)
+		chosen := make([]string, 0)
+		taken := make(map[string]bool, 0)
+		var search func() bool
+		search = func() bool {
+			*nodes++
+			if *nodes > exactCoverBudget {
+				return false
+			}
+			target, maxDeficit := -1, 0
+			for i, d := range c.deficits {
+				if d > maxDeficit {
+					target, maxDeficit = i, d
+				}
+			}
+			if target < 0 {
+				set := make([]string, len(chosen))
+				copy(set, chosen)
+				sort.Strings(set)
+				if id := strings.Join(set, ","); !seen[id] {
+					seen[id] = true
+					sets = append(sets, set)
+				}
+				return true
+			}
+			if len(chosen) + maxDeficit > limit {
+				return true
+			}
+			for _, option := range c.needs[target].Options {
+				if taken[option] {
+					continue
+				}
+				taken[option] = true
+				chosen = append(chosen, option)
+				c.take(option, 1)
+				ok := search()
+				c.take(option, -1)
+				chosen = chosen[:len(chosen)-1]
+				taken[option] = false
+				if !ok {
+					return false
+				}
+				if len(sets) >= MaxAlternatives {
+					return true
+				}
+			}
+			return true
+		}
+		if !search() {
+			return nil, false
+		}
+		if len(sets) > 0 {
+			return sets, true
+		}
+	}
+	return [][]string{}, true
+}
+
+func uncoverable(needs []Need) []string {
+	labels := make([]string, 0)
+	for _, need := range needs {
+		if len(need.Options) < need.Remaining {
+			labels = append(labels, need.Label())
+		}
+	}
+	return labels
+}
+
+// exactOverAlternatives solves every combination of the rules' alternatives
+// exactly and keeps the smallest sets, preferring combinations that leave
+// fewer requirements uncoverable. It reports false when there are too many
+// combinations or the search runs out of budget.
+func exactOverAlternatives(rules []ruleAlternatives) (CoverResult, bool) {
+	choices := 1
+	for _, rule := range rules {
+		choices *= len(rule.choices)
+		if !rule.complete || (choices > maxCoverChoices) {
+			return CoverResult{}, false
+		}
+	}
+	best := CoverResult{Size: -1}
+	seen := make(map[string]bool, 0)
+	nodes := 0
+	needs := make([]Need, 0)
+	var search func(i int) bool
+	search = func(i int) bool {
+		if i < len(rules) {
+			for _, choice := range rules[i].choices {
+				n := len(needs)
+				needs = append(needs, choice...)
+				ok := search(i+1)
+				needs = needs[:n]
+				if !ok {
+					return false
+				}
+			}
+			return true
+		}
+		c := newCover(needs)
+		if len(c.candidates) > exactCoverCandidates {
+			return false
+		}
+		sets, ok := c.exact(&nodes)
+		if !ok {
+			return false
+		}
+		missing := uncoverable(needs)
+		size := 0
+		if len(sets) > 0 {
+			size = len(sets[0])
+		}
+		if (best.Size < 0) || (len(missing) < len(best.Uncoverable)) || ((len(missing) == len(best.Uncoverable)) && (size < best.Size)) {
+			best = CoverResult{Size: size, Sets: make([][]string, 0), Uncoverable: missing}
+			seen = make(map[string]bool, 0)
+		} else if (len(missing) > len(best.Uncoverable)) || (size > best.Size) {
+			return true
+		}
+		for _, set := range sets {
+			if id := strings.Join(set, ","); !seen[id] && (len(best.Sets) < MaxAlternatives) {
+				seen[id] = true
+				best.Sets = append(best.Sets, set)
+			}
+		}
+		return true
+	}
+	if !search(0) {
+		return CoverResult{}, false
+	}
+	best.Exact = true
+	return best, true
+}
+
+// MinimumCourseSet finds the fewest courses that finish every outstanding
+// requirement, counting a course toward every requirement that lists it.
+// When a rule needs only some of its requirements, every choice of them is
+// tried. Small instances are solved exactly, with alternative optimal sets;
+// larger ones fall back to a greedy heuristic over the requirements
+// OutstandingNeeds picks, favoring courses counting toward the most
+// requirements.
+func MinimumCourseSet(student *types.Student) CoverResult {
+	rules := needAlternatives(student)
+	if result, ok := exactOverAlternatives(rules); ok {
+		return result
+	}
+	needs := make([]Need, 0)
+	for _, rule := range rules {
+		needs = append(needs, rule.choices[0]...)
+	}
+	result := CoverResult{Sets: [][]string{newCover(needs).greedy()}, Uncoverable: uncoverable(needs)}
+	result.Size = len(result.Sets[0])
+	return result
+}
@@ -0,0 +1,95 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package planners
+
+import (
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"reflect"
+	"testing"
+)
+
+func req(options ...string) types.Requirement {
+	return types.Requirement{Required: 1, Options: options}
+}
+
+func coverStudent(rules ...types.Rule) types.Student {
+	return types.Student{Courses: map[string]types.Course{}, Taken: map[string]bool{}, Blocks: []types.Block{{Title: "Major", Rules: rules}}}
+}
+
+func TestMinimumCourseSet(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       []types.Rule
+		size        int
+		sets        [][]string
+		uncoverable int
+	}{
+		{"nothing outstanding", []types.Rule{}, 0, [][]string{{}}, 0},
+		{"shared course", []types.Rule{
+			{Label: "A", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{req("COMPSCI161", "COMPSCI162")}},
+			{Label: "B", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{req("COMPSCI162", "COMPSCI163")}},
+		}, 1, [][]string{{"COMPSCI162"}}, 0},
+		{"alternatives of equal size", []types.Rule{
+			{Label: "A", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{req("COMPSCI161", "COMPSCI162")}},
+		}, 1, [][]string{{"COMPSCI161"}, {"COMPSCI162"}}, 0},
+		// OutstandingNeeds picks the first requirement of A, but the second one
+		// is shared with B.
+		{"requirement choice", []types.Rule{
+			{Label: "A", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{req("COMPSCI161"), req("COMPSCI171")}},
+			{Label: "B", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{req("COMPSCI171")}},
+		}, 1, [][]string{{"COMPSCI171"}}, 0},
+		{"uncoverable", []types.Rule{
+			{Label: "A", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{{Required: 2, Options: []string{"COMPSCI161"}}}},
+		}, 1, [][]string{{"COMPSCI161"}}, 1},
+	}
+	for _, test := range tests {
+		student := coverStudent(test.rules...)
+		result := MinimumCourseSet(&student)
+		if !result.Exact {
+			t.Errorf("%v: not solved exactly", test.name)
+		}
+		if result.Size != test.size {
+			t.Errorf("%v: Size = %d, want %d", test.name, result.Size, test.size)
+		}
+		if !reflect.DeepEqual(result.Sets, test.sets) {
+			t.Errorf("%v: Sets = %v, want %v", test.name, result.Sets, test.sets)
+		}
+		if len(result.Uncoverable) != test.uncoverable {
+			t.Errorf("%v: Uncoverable = %v, want %d", test.name, result.Uncoverable, test.uncoverable)
+		}
+	}
+}
+
+func TestMinimumCourseSetFallsBackToGreedy(t *testing.T) {
+	options := make([]string, 0)
+	for i := 0; i < exactCoverCandidates+1; i++ {
+		options = append(options, fmt.Sprintf("COMPSCI%d", 100+i))
+	}
+	student := coverStudent(types.Rule{Label: "Electives", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{{Required: 3, Options: options}}})
+	result := MinimumCourseSet(&student)
+	if result.Exact {
+		t.Errorf("MinimumCourseSet over %d candidates was reported exact", len(options))
+	}
+	if result.Size != 3 {
+		t.Errorf("Size = %d, want 3", result.Size)
+	}
+}
@@ -0,0 +1,206 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package planners
+
+import (
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"sort"
+	"strings"
+)
+
+/* Shortest Path to a Target Course */
+
+type PathStep struct {
+	Term    string   `json:"term"`
+	Courses []string `json:"courses"`
+}
+
+type Path struct {
+	Target       string     `json:"target"`
+	Reachable    bool       `json:"reachable"`
+	EligibleTerm string     `json:"eligibleTerm"`
+	Steps        []PathStep `json:"steps"`
+	Notes        []string   `json:"notes"`
+}
+
+// route is the cheapest way found to complete a course: the term index in
+// which it is taken and every course (with its term index) needed for it.
+type route struct {
+	term    int
+	courses map[string]int
+}
+
+func (r route) cost() (int, int) {
+	return r.term, len(r.courses)
+}
+
+func cheaper(a route, b route) bool {
+	termA, countA := a.cost()
+	termB, countB := b.cost()
+	return (termA < termB) || ((termA == termB) && (countA < countB))
+}
+
+type pathfinder struct {
+	*planner
+	start    string
+	memo     map[string]*route
+	visiting map[string]bool
+	cut      bool
+	notes    []string
+}
+
+func (f *pathfinder) termAt(i int) string {
+	term := f.start
+	for ; i > 0; i-- {
+		term = types.NextTerm(term)
+	}
+	return term
+}
+
+// ready returns the earliest term index by which every prerequisite group of
+// the course is met, choosing the cheapest alternative in each group.
+func (f *pathfinder) ready(key string) (route, bool) {
+	r := route{term: 0, courses: make(map[string]int, 0)}
+	course, _ := f.course(key)
+	for _, prereqsAND := range course.Prerequisites {
+		var best *route
+		plannable := false
+		for _, prereqOR := range prereqsAND {
+			prereq := types.ParsePrerequisite(prereqOR)
+			if (prereq.Negated && !f.student.Taken[prereq.Key]) || (!prereq.Negated && f.student.Taken[prereq.Key]) {
+				best = &route{term: 0, courses: map[string]int{}}
+				break
+			}
+			if prereq.Negated {
+				continue
+			}
+			if _, ok := f.course(prereq.Key); !ok {
+				continue
+			}
+			plannable = true
+			alternative, ok := f.complete(prereq.Key)
+			if !ok {
+				continue
+			}
+			candidate := route{term: alternative.term + 1, courses: alternative.courses}
+			if prereq.Coreq {
+				candidate.term = alternative.term
+			}
+			if (best == nil) || cheaper(candidate, *best) {
+				best = &candidate
+			}
+		}
+		if best == nil {
+			if !plannable {
+				f.notes = appendOnce(f.notes, fmt.Sprintf("%v also requires %v, which can't be planned", key, strings.Join(prereqsAND, " OR ")))
+				continue
+			}
+			return route{}, false
+		}
+		if best.term > r.term {
+			r.term = best.term
+		}
+		for k, t := range best.courses {
+			r.courses[k] = t
+		}
+	}
+	return r, true
+}
+
+// complete returns the cheapest route to completing the course: the first
+// term on or after its prerequisites are met in which it is likely offered.
+// Routes found while a prerequisite cycle was cut off depend on where the
+// search entered the cycle, so they aren't memoized.
+func (f *pathfinder) complete(key string) (route, bool) {
+	if r, ok := f.memo[key]; ok {
+		if r == nil {
+			return route{}, false
+		}
+		return *r, true
+	}
+	if f.visiting[key] {
+		f.cut = true
+		return route{}, false
+	}
+	f.visiting[key] = true
+	defer func() { f.visiting[key] = false }()
+	cut := f.cut
+	f.cut = false
+	defer func() { f.cut = f.cut || cut }()
+	
+	r, ok := f.ready(key)
+	if ok {
+		term := r.term
+		for (term < MaxPlanTerms) && !f.offered(key, f.termAt(term)) {
+			term++
+		}
+		ok = term < MaxPlanTerms
+		r.term = term
+		r.courses[key] = term
+	}
+	if !ok {
+		if !f.cut {
+			f.memo[key] = nil
+		}
+		return route{}, false
+	}
+	if !f.cut {
+		f.memo[key] = &r
+	}
+	return r, true
+}
+
+// PathTo finds the shortest sequence of terms and courses, starting with the
+// given term, after which the student is eligible to take the target course.
+func PathTo(student *types.Student, catalogue *types.Catalogue, target string, startTerm string) Path {
+	target = strings.Replace(strings.ToUpper(target), " ", "", -1)
+	path := Path{Target: target, Steps: make([]PathStep, 0), Notes: make([]string, 0)}
+	f := &pathfinder{planner: newPlanner(student, catalogue), start: startTerm, memo: make(map[string]*route, 0), visiting: make(map[string]bool, 0), notes: make([]string, 0)}
+	if _, ok := f.course(target); !ok {
+		path.Notes = append(path.Notes, fmt.Sprintf("Unknown course `%v`", target))
+		return path
+	}
+	
+	f.visiting[target] = true
+	r, ok := f.ready(target)
+	path.Notes = append(path.Notes, f.notes...)
+	if !ok {
+		path.Notes = append(path.Notes, "No combination of courses meets the prerequisites")
+		return path
+	}
+	path.Reachable = true
+	path.EligibleTerm = f.termAt(r.term)
+	
+	byTerm := make(map[int][]string, 0)
+	terms := make([]int, 0)
+	for key, t := range r.courses {
+		if _, ok := byTerm[t]; !ok {
+			terms = append(terms, t)
+		}
+		byTerm[t] = append(byTerm[t], key)
+	}
+	sort.Ints(terms)
+	for _, t := range terms {
+		sort.Strings(byTerm[t])
+		path.Steps = append(path.Steps, PathStep{Term: f.termAt(t), Courses: byTerm[t]})
+	}
+	return path
+}
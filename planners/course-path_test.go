@@ -0,0 +1,76 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package planners
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"reflect"
+	"testing"
+)
+
+func TestPathTo(t *testing.T) {
+	course := func(number string, prereqs ...[]string) types.Course {
+		return types.Course{Department: "COMPSCI", Number: number, Units: 4.0, Offered: everyQuarter, Prerequisites: prereqs}
+	}
+	tests := []struct {
+		name      string
+		courses   []types.Course
+		taken     []string
+		reachable bool
+		steps     []PathStep
+	}{
+		{"no prerequisites", []types.Course{course("200")}, []string{}, true, []PathStep{}},
+		{"chain", []types.Course{course("200", []string{"COMPSCI102"}), course("102", []string{"COMPSCI101"}), course("101")}, []string{}, true, []PathStep{
+			{Term: "2018-92", Courses: []string{"COMPSCI101"}},
+			{Term: "2019-03", Courses: []string{"COMPSCI102"}},
+		}},
+		{"prerequisite taken", []types.Course{course("200", []string{"COMPSCI102"}), course("102", []string{"COMPSCI101"}), course("101")}, []string{"COMPSCI102"}, true, []PathStep{}},
+		{"cheapest alternative", []types.Course{course("200", []string{"COMPSCI102", "COMPSCI103"}), course("102", []string{"COMPSCI101"}), course("101"), course("103")}, []string{}, true, []PathStep{
+			{Term: "2018-92", Courses: []string{"COMPSCI103"}},
+		}},
+		{"cycle only", []types.Course{course("200", []string{"COMPSCI101"}), course("101", []string{"COMPSCI102"}), course("102", []string{"COMPSCI101"})}, []string{}, false, []PathStep{}},
+		// 102 is first reached from inside 101, where the cycle back to 101 is
+		// cut off; it's still reachable through 101 via 103.
+		{"cycle cut-off isn't memoized", []types.Course{course("200", []string{"COMPSCI101"}, []string{"COMPSCI102"}), course("101", []string{"COMPSCI102", "COMPSCI103"}), course("102", []string{"COMPSCI101"}), course("103")}, []string{}, true, []PathStep{
+			{Term: "2018-92", Courses: []string{"COMPSCI103"}},
+			{Term: "2019-03", Courses: []string{"COMPSCI101"}},
+			{Term: "2019-14", Courses: []string{"COMPSCI102"}},
+		}},
+	}
+	for _, test := range tests {
+		catalogue := types.Catalogue{Courses: map[string]types.Course{}}
+		for _, c := range test.courses {
+			catalogue.Courses[c.Key()] = c
+		}
+		student := types.Student{Courses: map[string]types.Course{}, Taken: map[string]bool{}}
+		for _, key := range test.taken {
+			student.Taken[key] = true
+		}
+		path := PathTo(&student, &catalogue, "COMPSCI200", "2018-92")
+		if path.Reachable != test.reachable {
+			t.Errorf("%v: Reachable = %v, want %v (%v)", test.name, path.Reachable, test.reachable, path.Notes)
+			continue
+		}
+		if !reflect.DeepEqual(path.Steps, test.steps) {
+			t.Errorf("%v: Steps = %v, want %v", test.name, path.Steps, test.steps)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package planners
+
+import (
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"sort"
+	"strings"
+)
+
+/* Multi-Quarter Degree Planner */
+
+const DefaultUnits = 4.0
+const DefaultUnitCap = 16.0
+const MaxPlanTerms = 24
+
+type PlanOptions struct {
+	StartTerm string  `json:"startTerm"`
+	UnitCap   float64 `json:"unitCap"`
+	MaxTerms  int     `json:"maxTerms"`
+}
+
+type PlannedCourse struct {
+	Course  string   `json:"course"`
+	Units   float64  `json:"units"`
+	Reasons []string `json:"reasons"`
+}
+
+type PlannedTerm struct {
+	Term    string          `json:"term"`
+	Units   float64         `json:"units"`
+	Courses []PlannedCourse `json:"courses"`
+}
+
+type Plan struct {
+	Terms       []PlannedTerm   `json:"terms"`
+	Unscheduled []PlannedCourse `json:"unscheduled"`
+}
+
+type dependency struct {
+	key   string
+	coreq bool
+}
+
+type planner struct {
+	student    *types.Student
+	catalogue  *types.Catalogue
+	reasons    map[string][]string
+	order      []string
+	deps       map[string][][]dependency
+	unresolved map[string][]string
+}
+
+func newPlanner(student *types.Student, catalogue *types.Catalogue) *planner {
+	return &planner{
+		student:    student,
+		catalogue:  catalogue,
+		reasons:    make(map[string][]string, 0),
+		order:      make([]string, 0),
+		deps:       make(map[string][][]dependency, 0),
+		unresolved: make(map[string][]string, 0),
+	}
+}
+
+func (p *planner) course(key string) (types.Course, bool) {
+	if course, ok := p.student.Courses[key]; ok {
+		return course, true
+	}
+	course, ok := p.catalogue.Courses[key]
+	return course, ok
+}
+
+func (p *planner) units(key string) float64 {
+	if course, ok := p.course(key); ok && (course.Units > 0.0) {
+		return course.Units
+	}
+	return DefaultUnits
+}
+
+func (p *planner) offered(key string, term string) bool {
+	course, ok := p.course(key)
+	if !ok {
+		return true
+	}
+	// Courses without any history aren't ruled out.
+	forecast := course.PredictOffering(term, p.catalogue.Terms)
+	return forecast.Unknown || forecast.Likely()
+}
+
+func (p *planner) need(key string, reason string) {
+	if _, ok := p.reasons[key]; !ok {
+		p.order = append(p.order, key)
+	}
+	p.reasons[key] = append(p.reasons[key], reason)
+}
+
+// missingGroups returns the prerequisite groups of a course that the
+// student's completed courses do not yet satisfy. Courses completed below a
+// prerequisite's minimum grade don't satisfy it.
+func (p *planner) missingGroups(key string) [][]types.Prerequisite {
+	missing := make([][]types.Prerequisite, 0)
+	course, ok := p.course(key)
+	if !ok {
+		return missing
+	}
+	for _, prereqsAND := range course.Prerequisites {
+		satisfied := false
+		group := make([]types.Prerequisite, 0)
+		for _, prereqOR := range prereqsAND {
+			prereq := types.ParsePrerequisite(prereqOR)
+			if prereq.MetBy(p.student) {
+				satisfied = true
+				break
+			}
+			if !prereq.Negated {
+				group = append(group, prereq)
+			}
+		}
+		if !satisfied {
+			missing = append(missing, group)
+		}
+	}
+	return missing
+}
+
+// selectCourses picks the courses that fill each outstanding requirement,
+// preferring courses that count toward several requirements and courses
+// with the fewest missing prerequisites.
+func (p *planner) selectCourses(needs []Need) {
+	shared := make(map[string]int, 0)
+	for _, need := range needs {
+		for _, option := range need.Options {
+			shared[option]++
+		}
+	}
+	for _, need := range needs {
+		options := make([]string, len(need.Options))
+		copy(options, need.Options)
+		sort.SliceStable(options, func(i, j int) bool {
+			_, selectedI := p.reasons[options[i]]
+			_, selectedJ := p.reasons[options[j]]
+			if selectedI != selectedJ {
+				return selectedI
+			}
+			if shared[options[i]] != shared[options[j]] {
+				return shared[options[i]] > shared[options[j]]
+			}
+			return len(p.missingGroups(options[i])) < len(p.missingGroups(options[j]))
+		})
+		for i := 0; (i < need.Remaining) && (i < len(options)); i++ {
+			p.need(options[i], fmt.Sprintf("Fulfills %v", need.Label()))
+		}
+	}
+}
+
+// resolvePrerequisites adds the prerequisites of every selected course,
+// picking the alternative with the fewest missing prerequisites of its own
+// when no alternative is already part of the plan. Alternatives missing from
+// the catalogue can't be planned, and are noted as unresolved.
+func (p *planner) resolvePrerequisites() {
+	for i := 0; i < len(p.order); i++ {
+		key := p.order[i]
+		if _, ok := p.deps[key]; ok {
+			continue
+		}
+		groups := make([][]dependency, 0)
+		for _, group := range p.missingGroups(key) {
+			options := make([]dependency, 0)
+			for _, prereq := range group {
+				if _, ok := p.course(prereq.Key); ok {
+					options = append(options, dependency{key: prereq.Key, coreq: prereq.Coreq})
+				} else if !strings.HasSuffix(prereq.Key, standingKey("")) {
+					// Class standing isn't a course, so only the rest are noted.
+					p.unresolved[key] = appendOnce(p.unresolved[key], fmt.Sprintf("Prerequisite %v is not in the catalogue and can't be planned", prereq.Key))
+				}
+			}
+			if len(options) == 0 {
+				// Non-course prerequisites, such as class standing, can't be planned.
+				continue
+			}
+			chosen := -1
+			for j, option := range options {
+				if _, ok := p.reasons[option.key]; ok {
+					chosen = j
+					break
+				}
+			}
+			if chosen < 0 {
+				chosen = 0
+				for j, option := range options {
+					if len(p.missingGroups(option.key)) < len(p.missingGroups(options[chosen].key)) {
+						chosen = j
+					}
+				}
+				p.need(options[chosen].key, fmt.Sprintf("Prerequisite for %v", key))
+			}
+			groups = append(groups, []dependency{options[chosen]})
+		}
+		p.deps[key] = groups
+	}
+}
+
+// depth returns the length of the longest chain of planned courses that
+// depend on key, so courses on the critical path are scheduled first.
+func (p *planner) depth(key string, memo map[string]int, visiting map[string]bool) int {
+	if d, ok := memo[key]; ok {
+		return d
+	}
+	if visiting[key] {
+		return 0
+	}
+	visiting[key] = true
+	d := 0
+	for _, other := range p.order {
+		for _, group := range p.deps[other] {
+			for _, dep := range group {
+				if (dep.key == key) && !dep.coreq {
+					if od := p.depth(other, memo, visiting) + 1; od > d {
+						d = od
+					}
+				}
+			}
+		}
+	}
+	visiting[key] = false
+	memo[key] = d
+	return d
+}
+
+func (p *planner) eligible(key string, term int, completed map[string]int) (bool, []string) {
+	notes := make([]string, 0)
+	for _, group := range p.deps[key] {
+		satisfied := false
+		for _, dep := range group {
+			if t, ok := completed[dep.key]; ok {
+				if t < term {
+					satisfied = true
+					notes = append(notes, fmt.Sprintf("Prerequisite %v completed earlier", dep.key))
+				} else if (t == term) && dep.coreq {
+					satisfied = true
+					notes = append(notes, fmt.Sprintf("Corequisite %v taken concurrently", dep.key))
+				}
+			}
+		}
+		if !satisfied {
+			return false, nil
+		}
+	}
+	return true, notes
+}
+
+func forecastSummary(course types.Course, term string, terms []string) string {
+	forecast := course.PredictOffering(term, terms)
+	if forecast.Unknown {
+		return forecast.Pattern
+	}
+	return fmt.Sprintf("%.0f%% likely, %v", forecast.Probability*100.0, forecast.Pattern)
+}
+
+func GeneratePlan(student *types.Student, catalogue *types.Catalogue, options PlanOptions) Plan {
+	if options.UnitCap <= 0.0 {
+		options.UnitCap = DefaultUnitCap
+	}
+	if options.MaxTerms <= 0 {
+		options.MaxTerms = MaxPlanTerms
+	}
+	
+	p := newPlanner(student, catalogue)
+	p.selectCourses(OutstandingNeeds(student))
+	p.resolvePrerequisites()
+	
+	memo := make(map[string]int, 0)
+	remaining := make([]string, len(p.order))
+	copy(remaining, p.order)
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return p.depth(remaining[i], memo, map[string]bool{}) > p.depth(remaining[j], memo, map[string]bool{})
+	})
+	
+	plan := Plan{Terms: make([]PlannedTerm, 0), Unscheduled: make([]PlannedCourse, 0)}
+	completed := make(map[string]int, 0)
+	deferrals := make(map[string][]string, 0)
+	term := options.StartTerm
+	for i := 0; (i < options.MaxTerms) && (len(remaining) > 0); i++ {
+		planned := PlannedTerm{Term: term, Courses: make([]PlannedCourse, 0)}
+		for changed := true; changed; {
+			changed = false
+			for _, key := range remaining {
+				if _, ok := completed[key]; ok {
+					continue
+				}
+				ok, notes := p.eligible(key, i, completed)
+				if !ok {
+					continue
+				}
+				course, _ := p.course(key)
+				if !p.offered(key, term) {
+					deferrals[key] = appendOnce(deferrals[key], fmt.Sprintf("Not expected to be offered in %v (%v)", types.TermName(term), forecastSummary(course, term, p.catalogue.Terms)))
+					continue
+				}
+				units := p.units(key)
+				if planned.Units + units > options.UnitCap {
+					deferrals[key] = appendOnce(deferrals[key], fmt.Sprintf("Deferred from %v: %v-unit cap reached", types.TermName(term), options.UnitCap))
+					continue
+				}
+				reasons := make([]string, 0)
+				reasons = append(reasons, p.reasons[key]...)
+				if (len(notes) == 0) && (len(p.unresolved[key]) == 0) {
+					notes = append(notes, "All prerequisites already met")
+				}
+				reasons = append(reasons, notes...)
+				reasons = append(reasons, p.unresolved[key]...)
+				if d := p.depth(key, memo, map[string]bool{}); d > 0 {
+					reasons = append(reasons, fmt.Sprintf("Scheduled early: a chain of %d later planned course(s) depends on it", d))
+				}
+				reasons = append(reasons, deferrals[key]...)
+				completed[key] = i
+				planned.Units += units
+				planned.Courses = append(planned.Courses, PlannedCourse{Course: key, Units: units, Reasons: reasons})
+				changed = true
+			}
+		}
+		rest := make([]string, 0)
+		for _, key := range remaining {
+			if _, ok := completed[key]; !ok {
+				rest = append(rest, key)
+			}
+		}
+		remaining = rest
+		plan.Terms = append(plan.Terms, planned)
+		term = types.NextTerm(term)
+	}
+	
+	for _, key := range remaining {
+		reasons := make([]string, 0)
+		reasons = append(reasons, p.reasons[key]...)
+		reasons = append(reasons, p.unresolved[key]...)
+		reasons = append(reasons, deferrals[key]...)
+		reasons = append(reasons, fmt.Sprintf("Could not be scheduled within %d terms", options.MaxTerms))
+		plan.Unscheduled = append(plan.Unscheduled, PlannedCourse{Course: key, Units: p.units(key), Reasons: reasons})
+	}
+	return plan
+}
+
+func appendOnce(slice []string, s string) []string {
+	for _, e := range slice {
+		if e == s {
+			return slice
+		}
+	}
+	return append(slice, s)
+}
@@ -0,0 +1,123 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package planners
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// plannerCatalogue lists COMPSCI161 through COMPSCI165 at four units each,
+// with the given prerequisites.
+func plannerCatalogue(prerequisites map[string][][]string, offered map[string]map[string][]int) types.Catalogue {
+	courses := map[string]types.Course{}
+	for _, number := range []string{"161", "162", "163", "164", "165"} {
+		key := "COMPSCI" + number
+		courses[key] = types.Course{Department: "COMPSCI", Number: number, Units: 4.0, Prerequisites: prerequisites[key], Offered: offered[key]}
+	}
+	return types.Catalogue{Courses: courses, Terms: []string{}}
+}
+
+// plannedTerms describes each planned term as `term:course,course`.
+func plannedTerms(plan Plan) []string {
+	terms := make([]string, 0)
+	for _, term := range plan.Terms {
+		keys := make([]string, 0)
+		for _, course := range term.Courses {
+			keys = append(keys, course.Course)
+		}
+		terms = append(terms, term.Term + ":" + strings.Join(keys, ","))
+	}
+	return terms
+}
+
+func TestGeneratePlan(t *testing.T) {
+	chain := map[string][][]string{"COMPSCI162": {{"COMPSCI 161|C"}}, "COMPSCI163": {{"COMPSCI 162"}}}
+	fallOnly := map[string]map[string][]int{"COMPSCI161": {"F": {2014, 2015, 2016, 2017}}}
+	tests := []struct {
+		name          string
+		required      int
+		options       []string
+		prerequisites map[string][][]string
+		offered       map[string]map[string][]int
+		taken         map[string]string
+		maxTerms      int
+		terms         []string
+		unscheduled   []string
+	}{
+		{"within the unit cap", 4, []string{"COMPSCI161", "COMPSCI162", "COMPSCI163", "COMPSCI164"}, nil, nil, nil, 0,
+			[]string{"2019-03:COMPSCI161,COMPSCI162,COMPSCI163,COMPSCI164"}, []string{}},
+		{"over the unit cap", 5, []string{"COMPSCI161", "COMPSCI162", "COMPSCI163", "COMPSCI164", "COMPSCI165"}, nil, nil, nil, 0,
+			[]string{"2019-03:COMPSCI161,COMPSCI162,COMPSCI163,COMPSCI164", "2019-14:COMPSCI165"}, []string{}},
+		{"prerequisite chain", 1, []string{"COMPSCI163"}, chain, nil, nil, 0,
+			[]string{"2019-03:COMPSCI161", "2019-14:COMPSCI162", "2019-92:COMPSCI163"}, []string{}},
+		{"prerequisite met", 1, []string{"COMPSCI162"}, chain, nil, map[string]string{"COMPSCI161": "B"}, 0,
+			[]string{"2019-03:COMPSCI162"}, []string{}},
+		{"prerequisite below the minimum grade", 1, []string{"COMPSCI162"}, chain, nil, map[string]string{"COMPSCI161": "D"}, 0,
+			[]string{"2019-03:COMPSCI161", "2019-14:COMPSCI162"}, []string{}},
+		{"offered in Fall only", 1, []string{"COMPSCI161"}, nil, fallOnly, nil, 0,
+			[]string{"2019-03:", "2019-14:", "2019-92:COMPSCI161"}, []string{}},
+		{"chain longer than the plan", 1, []string{"COMPSCI163"}, chain, nil, nil, 2,
+			[]string{"2019-03:COMPSCI161", "2019-14:COMPSCI162"}, []string{"COMPSCI163"}},
+	}
+	for _, test := range tests {
+		rule := types.Rule{Label: "Electives", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{{Required: test.required, Options: test.options}}}
+		student := coverStudent(rule)
+		for key, grade := range test.taken {
+			student.Courses[key] = types.Course{Department: "COMPSCI", Number: key[7:], Units: 4.0, Grade: grade}
+			student.Taken[key] = true
+		}
+		catalogue := plannerCatalogue(test.prerequisites, test.offered)
+		plan := GeneratePlan(&student, &catalogue, PlanOptions{StartTerm: "2019-03", UnitCap: 16.0, MaxTerms: test.maxTerms})
+		if terms := plannedTerms(plan); !reflect.DeepEqual(terms, test.terms) {
+			t.Errorf("%v: terms = %v, want %v", test.name, terms, test.terms)
+		}
+		unscheduled := make([]string, 0)
+		for _, course := range plan.Unscheduled {
+			unscheduled = append(unscheduled, course.Course)
+		}
+		if !reflect.DeepEqual(unscheduled, test.unscheduled) {
+			t.Errorf("%v: unscheduled = %v, want %v", test.name, unscheduled, test.unscheduled)
+		}
+	}
+}
+
+func TestGeneratePlanNotesUnresolvedPrerequisites(t *testing.T) {
+	prerequisites := map[string][][]string{"COMPSCI162": {{"COMPSCI 99", "COMPSCI 161"}, {"JUNIOR STANDING ONLY"}}}
+	rule := types.Rule{Label: "Electives", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{req("COMPSCI162")}}
+	student := coverStudent(rule)
+	catalogue := plannerCatalogue(prerequisites, nil)
+	plan := GeneratePlan(&student, &catalogue, PlanOptions{StartTerm: "2019-03"})
+	if terms, expected := plannedTerms(plan), []string{"2019-03:COMPSCI161", "2019-14:COMPSCI162"}; !reflect.DeepEqual(terms, expected) {
+		t.Fatalf("terms = %v, want %v", terms, expected)
+	}
+	reasons := plan.Terms[1].Courses[0].Reasons
+	unresolved := make([]string, 0)
+	for _, reason := range reasons {
+		if strings.Contains(reason, "not in the catalogue") {
+			unresolved = append(unresolved, reason)
+		}
+	}
+	if expected := []string{"Prerequisite COMPSCI99 is not in the catalogue and can't be planned"}; !reflect.DeepEqual(unresolved, expected) {
+		t.Errorf("unresolved = %v, want %v (reasons %v)", unresolved, expected, reasons)
+	}
+}
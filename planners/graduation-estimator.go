@@ -0,0 +1,135 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package planners
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"math"
+	"sort"
+)
+
+/* Graduation Estimator */
+
+// DefaultUpperDivisionUnits is assumed when no other upper-division unit
+// requirement is given.
+const DefaultUpperDivisionUnits = 60.0
+
+type GraduationOptions struct {
+	StartTerm          string
+	Pace               float64
+	UpperDivisionUnits float64
+}
+
+func blockUnits(p *planner, needs []Need) []types.BlockUnits {
+	blocks := make([]types.BlockUnits, 0)
+	index := make(map[string]int, 0)
+	for _, block := range p.student.Blocks {
+		if _, ok := index[block.Title]; !ok {
+			index[block.Title] = len(blocks)
+			blocks = append(blocks, types.BlockUnits{Title: block.Title})
+		}
+	}
+	for _, need := range needs {
+		units := make([]float64, 0)
+		for _, option := range need.Options {
+			units = append(units, p.units(option))
+		}
+		sort.Float64s(units)
+		for i := 0; i < need.Remaining; i++ {
+			if i < len(units) {
+				blocks[index[need.Block]].UnitsRemaining += units[i]
+			} else {
+				blocks[index[need.Block]].UnitsRemaining += DefaultUnits
+			}
+		}
+	}
+	return blocks
+}
+
+// EstimateGraduation plans the remaining requirements at the given pace, then
+// fills spare capacity with elective units until the degree unit minimum is
+// met, including enough upper-division units. Courses that land after the
+// term the units alone would allow are reported as constraints, along with
+// the reasons they were placed there. The earliest term is left empty when
+// some courses couldn't be scheduled at all.
+func EstimateGraduation(student *types.Student, catalogue *types.Catalogue, options GraduationOptions) types.GraduationEstimate {
+	if options.Pace <= 0.0 {
+		options.Pace = DefaultUnitCap
+	}
+	if options.UpperDivisionUnits <= 0.0 {
+		options.UpperDivisionUnits = DefaultUpperDivisionUnits
+	}
+	p := newPlanner(student, catalogue)
+	plan := GeneratePlan(student, catalogue, PlanOptions{StartTerm: options.StartTerm, UnitCap: options.Pace})
+	
+	plannedUnits, plannedUpper := 0.0, 0.0
+	for _, term := range plan.Terms {
+		plannedUnits += term.Units
+		for _, planned := range term.Courses {
+			if course, ok := p.course(planned.Course); ok && course.IsUpperDivision() {
+				plannedUpper += planned.Units
+			}
+		}
+	}
+	for _, planned := range plan.Unscheduled {
+		plannedUnits += planned.Units
+	}
+	
+	estimate := types.GraduationEstimate{Pace: options.Pace, Constraints: make([]types.Constraint, 0)}
+	estimate.UnitsRemaining = math.Max(types.DegreeUnits - student.CreditsApplied, plannedUnits)
+	estimate.UpperDivisionUnitsRemaining = math.Max(options.UpperDivisionUnits - student.UpperDivisionCredits(), 0.0)
+	estimate.Blocks = blockUnits(p, OutstandingNeeds(student))
+	
+	// Upper-division units the plan doesn't cover must come from electives,
+	// even once the degree unit minimum is met.
+	electives := math.Max(estimate.UnitsRemaining - plannedUnits, estimate.UpperDivisionUnitsRemaining - plannedUpper)
+	term := options.StartTerm
+	for _, planned := range plan.Terms {
+		fill := math.Min(options.Pace - planned.Units, electives)
+		electives -= fill
+		if planned.Units + fill > 0.0 {
+			estimate.EarliestTerm = planned.Term
+		}
+		term = types.NextTerm(planned.Term)
+	}
+	for ; electives > 0.0; term = types.NextTerm(term) {
+		electives -= options.Pace
+		estimate.EarliestTerm = term
+	}
+	// Courses that couldn't be placed leave no term in which graduation is
+	// certain.
+	if len(plan.Unscheduled) > 0 {
+		estimate.EarliestTerm = ""
+	}
+	
+	unconstrained := int(math.Ceil(estimate.UnitsRemaining / options.Pace))
+	for i, planned := range plan.Terms {
+		if i < unconstrained {
+			continue
+		}
+		for _, course := range planned.Courses {
+			estimate.Constraints = append(estimate.Constraints, types.Constraint{Course: course.Course, Term: planned.Term, Reasons: course.Reasons})
+		}
+	}
+	for _, course := range plan.Unscheduled {
+		estimate.Constraints = append(estimate.Constraints, types.Constraint{Course: course.Course, Reasons: course.Reasons})
+	}
+	return estimate
+}
@@ -0,0 +1,64 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package planners
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"testing"
+)
+
+var everyQuarter = map[string][]int{"F": {2015, 2016, 2017}, "W": {2016, 2017, 2018}, "S": {2016, 2017, 2018}}
+
+func requiring(keys ...string) []types.Block {
+	rule := types.Rule{Label: "Required", Kind: types.CourseRule, Required: len(keys), Requirements: make([]types.Requirement, 0)}
+	for _, key := range keys {
+		rule.Requirements = append(rule.Requirements, types.Requirement{Required: 1, Options: []string{key}})
+	}
+	return []types.Block{{Title: "Major", Rules: []types.Rule{rule}}}
+}
+
+func TestEstimateGraduation(t *testing.T) {
+	catalogue := types.Catalogue{Courses: map[string]types.Course{
+		"COMPSCI161": {Department: "COMPSCI", Number: "161", Units: 4.0, Offered: everyQuarter},
+		"COMPSCI21":  {Department: "COMPSCI", Number: "21", Units: 4.0, Offered: everyQuarter},
+		"COMPSCI199": {Department: "COMPSCI", Number: "199", Units: 20.0, Offered: everyQuarter},
+	}}
+	tests := []struct {
+		name     string
+		credits  float64
+		required []string
+		upper    float64
+		earliest string
+	}{
+		{"units only", 150.0, []string{"COMPSCI21"}, 4.0, "2019-03"},
+		{"upper division fits in one term", 176.0, []string{"COMPSCI161"}, 12.0, "2018-92"},
+		{"upper division pushes graduation back", 176.0, []string{"COMPSCI161"}, 40.0, "2019-14"},
+		{"lower division doesn't count as upper", 176.0, []string{"COMPSCI21"}, 20.0, "2019-03"},
+		{"unschedulable course", 176.0, []string{"COMPSCI199"}, 4.0, ""},
+	}
+	for _, test := range tests {
+		student := types.Student{CreditsApplied: test.credits, Courses: map[string]types.Course{}, Taken: map[string]bool{}, Blocks: requiring(test.required...)}
+		estimate := EstimateGraduation(&student, &catalogue, GraduationOptions{StartTerm: "2018-92", Pace: 16.0, UpperDivisionUnits: test.upper})
+		if estimate.EarliestTerm != test.earliest {
+			t.Errorf("%v: EarliestTerm = %q, want %q", test.name, estimate.EarliestTerm, test.earliest)
+		}
+	}
+}
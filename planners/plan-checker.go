@@ -0,0 +1,241 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package planners
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/* Plan File Checker */
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// PlanFile maps each term (e.g. `2018-03`) to the course keys planned for it.
+type PlanFile map[string][]string
+
+func PlanFromFile(path string) (PlanFile, error) {
+	fileBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var plan PlanFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(fileBytes, &plan)
+	default:
+		err = json.Unmarshal(fileBytes, &plan)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+type Finding struct {
+	Severity string `json:"severity"`
+	Course   string `json:"course"`
+	Message  string `json:"message"`
+}
+
+type TermFindings struct {
+	Term     string    `json:"term"`
+	Units    float64   `json:"units"`
+	Findings []Finding `json:"findings"`
+}
+
+type PlanCheck struct {
+	Terms        []TermFindings `json:"terms"`
+	Requirements []Finding      `json:"requirements"`
+}
+
+func (check PlanCheck) HasErrors() bool {
+	for _, term := range check.Terms {
+		for _, finding := range term.Findings {
+			if finding.Severity == SeverityError {
+				return true
+			}
+		}
+	}
+	for _, finding := range check.Requirements {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizedKey(key string) string {
+	return strings.Replace(strings.ToUpper(key), " ", "", -1)
+}
+
+// standingKey matches the standing keys added to `Student.Taken` by the parser.
+func standingKey(standing string) string {
+	return strings.Replace(fmt.Sprintf("%v STANDING ONLY", standing), " ", "", -1)
+}
+
+func CheckPlan(student *types.Student, catalogue *types.Catalogue, plan PlanFile, unitCap float64) PlanCheck {
+	if unitCap <= 0.0 {
+		unitCap = DefaultUnitCap
+	}
+	p := newPlanner(student, catalogue)
+	
+	terms := make([]string, 0)
+	for term := range plan {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	
+	plannedIn := make(map[string]string, 0)
+	check := PlanCheck{Terms: make([]TermFindings, 0), Requirements: make([]Finding, 0)}
+	projected := *student
+	for _, term := range terms {
+		findings := make([]Finding, 0)
+		add := func(severity, course, format string, args ...interface{}) {
+			findings = append(findings, Finding{Severity: severity, Course: course, Message: fmt.Sprintf(format, args...)})
+		}
+		if !types.IsAcademicTerm(term) {
+			add(SeverityError, "", "`%v` is not a Fall, Winter or Spring term (expected e.g. 2018-92, 2018-03, 2018-14)", term)
+		}
+		
+		keys := make([]string, 0)
+		for _, key := range plan[term] {
+			keys = append(keys, normalizedKey(key))
+		}
+		units := 0.0
+		for _, key := range keys {
+			if _, ok := p.course(key); !ok {
+				add(SeverityError, key, "Unknown course")
+				continue
+			}
+			units += p.units(key)
+			if student.Taken[key] {
+				add(SeverityWarning, key, "Already completed")
+			}
+			if earlier, ok := plannedIn[key]; ok {
+				add(SeverityWarning, key, "Already planned in %v", types.TermName(earlier))
+			}
+			
+			course, _ := p.course(key)
+			if types.IsAcademicTerm(term) && !p.offered(key, term) {
+				add(SeverityWarning, key, "Not expected to be offered in %v (%v)", types.TermName(term), forecastSummary(course, term, p.catalogue.Terms))
+			}
+			
+			for _, prereqsAND := range course.Prerequisites {
+				if message, ok := checkGroup(prereqsAND, term, keys, plannedIn, &projected); !ok {
+					add(SeverityError, key, "Prerequisite not met: %v", message)
+				}
+			}
+		}
+		if units > unitCap {
+			add(SeverityError, "", "%v units planned, exceeding the %v-unit cap", units, unitCap)
+		}
+		for _, key := range keys {
+			if _, ok := plannedIn[key]; !ok {
+				plannedIn[key] = term
+			}
+		}
+		projected.CreditsApplied += units
+		check.Terms = append(check.Terms, TermFindings{Term: term, Units: units, Findings: findings})
+	}
+	
+	for _, alternatives := range needAlternatives(student) {
+		check.Requirements = append(check.Requirements, checkChoices(alternatives, plannedIn)...)
+	}
+	return check
+}
+
+// checkChoices reports on the first way of completing a rule that the plan
+// fills, or else on the way it comes closest to filling.
+func checkChoices(alternatives ruleAlternatives, plannedIn map[string]string) []Finding {
+	var best []Finding
+	bestMissing := -1
+	for _, choice := range alternatives.choices {
+		findings := make([]Finding, 0)
+		missing := 0
+		for _, need := range choice {
+			filled := make([]string, 0)
+			for _, option := range need.Options {
+				if _, ok := plannedIn[option]; ok {
+					filled = append(filled, option)
+				}
+			}
+			if len(filled) >= need.Remaining {
+				findings = append(findings, Finding{Severity: SeverityInfo, Message: fmt.Sprintf("%v is filled by %v", need.Label(), strings.Join(filled, ", "))})
+			} else {
+				missing += need.Remaining - len(filled)
+				findings = append(findings, Finding{Severity: SeverityError, Message: fmt.Sprintf("%v needs %d more course(s) from: %v", need.Label(), need.Remaining - len(filled), strings.Join(need.Options, ", "))})
+			}
+		}
+		if missing == 0 {
+			return findings
+		}
+		if (bestMissing < 0) || (missing < bestMissing) {
+			best, bestMissing = findings, missing
+		}
+	}
+	if !alternatives.complete && (len(alternatives.choices) > 0) {
+		best = append(best, Finding{Severity: SeverityWarning, Message: fmt.Sprintf("Only the first %d ways of completing %v were checked", maxNeedAlternatives, alternatives.choices[0][0].Label())})
+	}
+	return best
+}
+
+// checkGroup reports whether one group of alternative prerequisites is met
+// by courses completed before, or corequisites planned during, the term.
+// Class standing is projected from the units planned in earlier terms.
+func checkGroup(prereqsAND []string, term string, termKeys []string, plannedIn map[string]string, projected *types.Student) (string, bool) {
+	alternatives := make([]string, 0)
+	for _, prereqOR := range prereqsAND {
+		prereq := types.ParsePrerequisite(prereqOR)
+		_, planned := plannedIn[prereq.Key]
+		if prereq.Negated {
+			if !projected.Taken[prereq.Key] && !planned {
+				return "", true
+			}
+		} else if projected.Taken[prereq.Key] || planned {
+			return "", true
+		} else if (prereq.Key == standingKey(projected.ClassLevel())) || (prereq.Key == standingKey(projected.Standing())) {
+			return "", true
+		} else if prereq.Coreq && contains(termKeys, prereq.Key) {
+			return "", true
+		}
+		alternatives = append(alternatives, strings.Split(prereqOR, "|")[0])
+	}
+	return strings.Join(alternatives, " OR "), false
+}
+
+func contains(slice []string, s string) bool {
+	for _, e := range slice {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
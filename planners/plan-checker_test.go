@@ -0,0 +1,106 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package planners
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"strings"
+	"testing"
+)
+
+func checkerCatalogue() types.Catalogue {
+	courses := map[string]types.Course{}
+	for _, key := range []string{"COMPSCI161", "COMPSCI162", "COMPSCI163", "COMPSCI164", "COMPSCI165"} {
+		courses[key] = types.Course{Department: "COMPSCI", Number: key[7:], Units: 4.0}
+	}
+	course := courses["COMPSCI162"]
+	course.Prerequisites = [][]string{{"COMPSCI 161|C"}}
+	courses["COMPSCI162"] = course
+	return types.Catalogue{Courses: courses, Terms: []string{}}
+}
+
+// errorMessages lists the messages of the plan's error findings.
+func errorMessages(check PlanCheck) []string {
+	messages := make([]string, 0)
+	for _, term := range check.Terms {
+		for _, finding := range term.Findings {
+			if finding.Severity == SeverityError {
+				messages = append(messages, strings.TrimSpace(finding.Course + " " + finding.Message))
+			}
+		}
+	}
+	for _, finding := range check.Requirements {
+		if finding.Severity == SeverityError {
+			messages = append(messages, finding.Message)
+		}
+	}
+	return messages
+}
+
+func TestCheckPlan(t *testing.T) {
+	tests := []struct {
+		name   string
+		plan   PlanFile
+		errors []string
+	}{
+		{"prerequisite planned first", PlanFile{"2018-92": {"COMPSCI 161"}, "2019-03": {"COMPSCI 162"}}, []string{}},
+		{"prerequisite planned later", PlanFile{"2018-92": {"COMPSCI 162"}, "2019-03": {"COMPSCI 161"}}, []string{"COMPSCI162 Prerequisite not met: COMPSCI 161"}},
+		{"prerequisite in the same term", PlanFile{"2018-92": {"COMPSCI 161", "COMPSCI 162"}}, []string{"COMPSCI162 Prerequisite not met: COMPSCI 161"}},
+		{"unit cap met", PlanFile{"2018-92": {"COMPSCI 161", "COMPSCI 163", "COMPSCI 164", "COMPSCI 165"}}, []string{}},
+		{"unit cap exceeded", PlanFile{"2018-92": {"COMPSCI 161", "COMPSCI 162", "COMPSCI 163", "COMPSCI 164", "COMPSCI 165"}}, []string{
+			"COMPSCI162 Prerequisite not met: COMPSCI 161",
+			"20 units planned, exceeding the 16-unit cap",
+		}},
+		{"unknown course", PlanFile{"2018-92": {"COMPSCI 999"}}, []string{"COMPSCI999 Unknown course"}},
+	}
+	for _, test := range tests {
+		student := coverStudent()
+		catalogue := checkerCatalogue()
+		messages := errorMessages(CheckPlan(&student, &catalogue, test.plan, 16.0))
+		if strings.Join(messages, "\n") != strings.Join(test.errors, "\n") {
+			t.Errorf("%v: errors = %q, want %q", test.name, messages, test.errors)
+		}
+	}
+}
+
+func TestCheckPlanAlternatives(t *testing.T) {
+	series := types.Rule{Label: "Series", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{
+		{Required: 2, Options: []string{"COMPSCI161", "COMPSCI162"}},
+		{Required: 2, Options: []string{"COMPSCI163", "COMPSCI164"}},
+	}}
+	tests := []struct {
+		name   string
+		plan   PlanFile
+		errors []string
+	}{
+		{"first choice", PlanFile{"2018-92": {"COMPSCI 161"}, "2019-03": {"COMPSCI 162"}}, []string{}},
+		{"second choice", PlanFile{"2018-92": {"COMPSCI 163", "COMPSCI 164"}}, []string{}},
+		{"closest choice", PlanFile{"2018-92": {"COMPSCI 163"}}, []string{"Major: Series needs 1 more course(s) from: COMPSCI163, COMPSCI164"}},
+		{"nothing planned", PlanFile{}, []string{"Major: Series needs 2 more course(s) from: COMPSCI161, COMPSCI162"}},
+	}
+	for _, test := range tests {
+		student := coverStudent(series)
+		catalogue := checkerCatalogue()
+		messages := errorMessages(CheckPlan(&student, &catalogue, test.plan, 16.0))
+		if strings.Join(messages, "\n") != strings.Join(test.errors, "\n") {
+			t.Errorf("%v: errors = %q, want %q", test.name, messages, test.errors)
+		}
+	}
+}
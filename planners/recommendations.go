@@ -0,0 +1,127 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package planners
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"math"
+	"sort"
+	"strings"
+)
+
+/* "Next Best Course" Recommendations */
+
+// ShortlistLength is the number of recommendations shown for each term.
+const ShortlistLength = 5
+
+const (
+	satisfiesWeight = 2.0
+	unlocksWeight   = 1.0
+	offeredWeight   = 3.0
+	rarityWeight    = 1.5
+)
+
+// unlocks counts the untaken courses that transitively require the course,
+// following `RequiredBy` through the catalogue.
+func (p *planner) unlocks(key string, memo map[string]int) int {
+	if n, ok := memo[key]; ok {
+		return n
+	}
+	seen := make(map[string]bool, 0)
+	queue := []string{key}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		course, ok := p.course(current)
+		if !ok {
+			continue
+		}
+		for _, group := range course.RequiredBy {
+			for _, number := range group.Numbers {
+				next := strings.Replace(strings.ToUpper(group.Department + number), " ", "", -1)
+				if !seen[next] && (next != key) && !p.student.Taken[next] {
+					seen[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+	memo[key] = len(seen)
+	return len(seen)
+}
+
+// rarity is the chance, averaged over the next year, that the course is not
+// offered in a given quarter.
+func rarity(course types.Course, term string, terms []string) float64 {
+	total := 0.0
+	for i := 0; i < 3; i++ {
+		total += course.PredictOffering(term, terms).Probability
+		term = types.NextTerm(term)
+	}
+	return 1.0 - total/3.0
+}
+
+// Recommend ranks the unfinished requirement options whose prerequisites the
+// student has cleared, for the given term and the two after it. Options that
+// count toward more requirements, unlock more downstream courses, are offered
+// in the term and are rarely offered rank higher. Each shortlist holds at most
+// n recommendations, or all of them when n is 0.
+func Recommend(student *types.Student, catalogue *types.Catalogue, term string, n int) []types.Shortlist {
+	p := newPlanner(student, catalogue)
+	satisfies := make(map[string][]string, 0)
+	order := make([]string, 0)
+	for _, need := range OutstandingNeeds(student) {
+		for _, option := range need.Options {
+			if _, ok := satisfies[option]; !ok {
+				order = append(order, option)
+			}
+			satisfies[option] = appendOnce(satisfies[option], need.Label())
+		}
+	}
+	
+	memo := make(map[string]int, 0)
+	shortlists := make([]types.Shortlist, 0)
+	for i := 0; i < 3; i++ {
+		recommendations := make([]types.Recommendation, 0)
+		for _, key := range order {
+			course, ok := p.course(key)
+			if !ok || !course.ClearedPrereqs(student) {
+				continue
+			}
+			// The current term's schedule is known, later terms are forecast.
+			offered := (len(course.Classes[term]) > 0) || ((i > 0) && course.PredictOffering(term, catalogue.Terms).Likely())
+			r := types.Recommendation{Course: key, Satisfies: satisfies[key], Unlocks: p.unlocks(key, memo), Offered: offered, Rarity: rarity(course, term, catalogue.Terms)}
+			r.Score = satisfiesWeight*float64(len(r.Satisfies)) + unlocksWeight*math.Log(1.0 + float64(r.Unlocks))
+			if r.Offered {
+				r.Score += offeredWeight + rarityWeight*r.Rarity
+			}
+			recommendations = append(recommendations, r)
+		}
+		sort.SliceStable(recommendations, func(a, b int) bool {
+			return recommendations[a].Score > recommendations[b].Score
+		})
+		if (n > 0) && (len(recommendations) > n) {
+			recommendations = recommendations[:n]
+		}
+		shortlists = append(shortlists, types.Shortlist{Term: term, Recommendations: recommendations})
+		term = types.NextTerm(term)
+	}
+	return shortlists
+}
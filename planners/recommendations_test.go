@@ -0,0 +1,150 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package planners
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"reflect"
+	"testing"
+)
+
+func recommended(shortlist types.Shortlist) []string {
+	keys := make([]string, 0)
+	for _, r := range shortlist.Recommendations {
+		keys = append(keys, r.Course)
+	}
+	return keys
+}
+
+func TestRecommend(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         [][]string
+		prerequisites map[string][][]string
+		requiredBy    map[string][]string
+		scheduled     []string
+		taken         []string
+		n             int
+		expected      []string
+	}{
+		{"in requirement order", [][]string{{"COMPSCI161", "COMPSCI162", "COMPSCI163"}}, nil, nil, nil, nil, 0,
+			[]string{"COMPSCI161", "COMPSCI162", "COMPSCI163"}},
+		{"counts toward more requirements", [][]string{{"COMPSCI161", "COMPSCI162"}, {"COMPSCI162", "COMPSCI163"}}, nil, nil, nil, nil, 0,
+			[]string{"COMPSCI162", "COMPSCI161", "COMPSCI163"}},
+		{"unlocks more courses", [][]string{{"COMPSCI161", "COMPSCI162"}}, nil, map[string][]string{"COMPSCI162": {"164"}, "COMPSCI164": {"165"}}, nil, nil, 0,
+			[]string{"COMPSCI162", "COMPSCI161"}},
+		{"offered in the term", [][]string{{"COMPSCI161", "COMPSCI162"}}, nil, nil, []string{"COMPSCI162"}, nil, 0,
+			[]string{"COMPSCI162", "COMPSCI161"}},
+		{"offered outweighs unlocks", [][]string{{"COMPSCI161", "COMPSCI162"}}, nil, map[string][]string{"COMPSCI161": {"164"}}, []string{"COMPSCI162"}, nil, 0,
+			[]string{"COMPSCI162", "COMPSCI161"}},
+		{"prerequisites not cleared", [][]string{{"COMPSCI161", "COMPSCI162"}}, map[string][][]string{"COMPSCI162": {{"COMPSCI 99"}}}, nil, nil, nil, 0,
+			[]string{"COMPSCI161"}},
+		{"prerequisites cleared", [][]string{{"COMPSCI161", "COMPSCI162"}}, map[string][][]string{"COMPSCI162": {{"COMPSCI 99"}}}, nil, nil, []string{"COMPSCI99"}, 0,
+			[]string{"COMPSCI161", "COMPSCI162"}},
+		{"not in the catalogue", [][]string{{"COMPSCI161", "COMPSCI999"}}, nil, nil, nil, nil, 0,
+			[]string{"COMPSCI161"}},
+		{"shortlist length", [][]string{{"COMPSCI161", "COMPSCI162", "COMPSCI163", "COMPSCI164"}}, nil, nil, []string{"COMPSCI164"}, nil, 2,
+			[]string{"COMPSCI164", "COMPSCI161"}},
+		{"nothing outstanding", [][]string{}, nil, nil, nil, nil, 0,
+			[]string{}},
+	}
+	for _, test := range tests {
+		rules := make([]types.Rule, 0)
+		for _, options := range test.rules {
+			rules = append(rules, types.Rule{Label: options[0], Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{req(options...)}})
+		}
+		student := coverStudent(rules...)
+		for _, key := range test.taken {
+			student.Courses[key] = types.Course{Department: "COMPSCI", Number: key[7:], Units: 4.0, Grade: "A"}
+			student.Taken[key] = true
+		}
+		catalogue := plannerCatalogue(test.prerequisites, nil)
+		catalogue.Terms = []string{"2019-03"}
+		for key, numbers := range test.requiredBy {
+			course := catalogue.Courses[key]
+			course.RequiredBy = types.CourseGroups{{Department: "COMPSCI", Numbers: numbers}}
+			catalogue.Courses[key] = course
+		}
+		for _, key := range test.scheduled {
+			course := catalogue.Courses[key]
+			course.Classes = map[string][]types.Class{"2019-03": {{Code: "34000", Type: "Lec"}}}
+			catalogue.Courses[key] = course
+		}
+		shortlists := Recommend(&student, &catalogue, "2019-03", test.n)
+		if len(shortlists) != 3 {
+			t.Errorf("%v: %d shortlists, want 3", test.name, len(shortlists))
+			continue
+		}
+		if keys := recommended(shortlists[0]); !reflect.DeepEqual(keys, test.expected) {
+			t.Errorf("%v: recommended = %v, want %v", test.name, keys, test.expected)
+		}
+	}
+}
+
+func TestRecommendForecastsLaterTerms(t *testing.T) {
+	rule := types.Rule{Label: "Electives", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{req("COMPSCI161", "COMPSCI162")}}
+	student := coverStudent(rule)
+	student.Courses["COMPSCI163"] = types.Course{Department: "COMPSCI", Number: "163", Units: 4.0, Grade: "A"}
+	student.Taken["COMPSCI163"] = true
+	fallOnly := map[string]map[string][]int{"COMPSCI161": {"F": {2014, 2015, 2016, 2017, 2018}}}
+	catalogue := plannerCatalogue(nil, fallOnly)
+	course := catalogue.Courses["COMPSCI162"]
+	course.RequiredBy = types.CourseGroups{{Department: "COMPSCI", Numbers: []string{"163", "164"}}}
+	catalogue.Courses["COMPSCI162"] = course
+	
+	shortlists := Recommend(&student, &catalogue, "2019-14", 0)
+	tests := []struct {
+		term     string
+		offered  bool
+		expected []string
+	}{
+		{"2019-14", false, []string{"COMPSCI162", "COMPSCI161"}},
+		{"2019-92", true, []string{"COMPSCI161", "COMPSCI162"}},
+		{"2020-03", false, []string{"COMPSCI162", "COMPSCI161"}},
+	}
+	for i, test := range tests {
+		if shortlists[i].Term != test.term {
+			t.Errorf("shortlist %d: term = %v, want %v", i, shortlists[i].Term, test.term)
+		}
+		if keys := recommended(shortlists[i]); !reflect.DeepEqual(keys, test.expected) {
+			t.Errorf("%v: recommended = %v, want %v", test.term, keys, test.expected)
+			continue
+		}
+		for _, r := range shortlists[i].Recommendations {
+			if r.Course == "COMPSCI161" {
+				if r.Offered != test.offered {
+					t.Errorf("%v: COMPSCI161 offered = %v, want %v", test.term, r.Offered, test.offered)
+				}
+				if (r.Rarity < 0.6) || (r.Rarity > 0.7) {
+					t.Errorf("%v: COMPSCI161 rarity = %v, want about 2/3", test.term, r.Rarity)
+				}
+			} else {
+				// Taken courses aren't counted as unlocked.
+				if r.Unlocks != 1 {
+					t.Errorf("%v: COMPSCI162 unlocks = %d, want 1", test.term, r.Unlocks)
+				}
+				if !reflect.DeepEqual(r.Satisfies, []string{"Major: Electives"}) {
+					t.Errorf("%v: COMPSCI162 satisfies = %v, want [Major: Electives]", test.term, r.Satisfies)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package planners
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"sort"
+)
+
+/* Outstanding Requirements */
+
+type Need struct {
+	Block     string   `json:"block"`
+	Rule      string   `json:"rule"`
+	Remaining int      `json:"remaining"`
+	Options   []string `json:"options"`
+}
+
+func (need Need) Label() string {
+	return need.Block + ": " + need.Rule
+}
+
+// maxNeedAlternatives caps how many ways of completing a single rule are
+// listed.
+const maxNeedAlternatives = 64
+
+// OutstandingNeeds lists, for every unfinished rule, the requirements still
+// needed to complete it. When a rule needs only some of its requirements,
+// the ones closest to completion are chosen.
+func OutstandingNeeds(student *types.Student) []Need {
+	needs := make([]Need, 0)
+	for _, alternatives := range needAlternatives(student) {
+		needs = append(needs, alternatives.choices[0]...)
+	}
+	return needs
+}
+
+type ruleAlternatives struct {
+	choices  [][]Need
+	complete bool
+}
+
+// needAlternatives lists, for every unfinished rule, each way of choosing the
+// requirements still needed to complete it, starting with the ones closest to
+// completion. It notes when a rule has more than maxNeedAlternatives ways.
+func needAlternatives(student *types.Student) []ruleAlternatives {
+	rules := make([]ruleAlternatives, 0)
+	for _, block := range student.Blocks {
+		for _, rule := range block.Flatten(student) {
+			if rule.IsCompleted(student) {
+				continue
+			}
+			requirements, _ := rule.Check(student)
+			completedCount := 0
+			pending := make([]types.Requirement, 0)
+			for _, req := range requirements {
+				if req.IsCompleted() {
+					completedCount++
+				} else {
+					pending = append(pending, req)
+				}
+			}
+			sort.SliceStable(pending, func(i, j int) bool {
+				return remaining(pending[i]) < remaining(pending[j])
+			})
+			k := rule.Required - completedCount
+			if k > len(pending) {
+				k = len(pending)
+			} else if k < 0 {
+				k = 0
+			}
+			alternatives := ruleAlternatives{choices: make([][]Need, 0)}
+			alternatives.complete = choose(len(pending), k, func(combination []int) bool {
+				if len(alternatives.choices) == maxNeedAlternatives {
+					return false
+				}
+				choice := make([]Need, 0)
+				for _, i := range combination {
+					req := pending[i]
+					options := make([]string, 0)
+					for _, option := range req.Options {
+						// Classes taken with a grade that doesn't count have to
+						// be repeated, so they stay options.
+						if ok, _ := rule.Qualifiers.Admits(student.Courses[option]); !student.Taken[option] || !ok {
+							options = append(options, option)
+						}
+					}
+					choice = append(choice, Need{Block: block.Title, Rule: rule.Label, Remaining: remaining(req), Options: options})
+				}
+				alternatives.choices = append(alternatives.choices, choice)
+				return true
+			})
+			rules = append(rules, alternatives)
+		}
+	}
+	return rules
+}
+
+// choose visits the k-element subsets of 0..n-1 in order, stopping once visit
+// returns false. It reports whether every subset was visited.
+func choose(n int, k int, visit func(combination []int) bool) bool {
+	combination := make([]int, 0, k)
+	var build func(start int) bool
+	build = func(start int) bool {
+		if len(combination) == k {
+			c := make([]int, k)
+			copy(c, combination)
+			return visit(c)
+		}
+		for i := start; i < n; i++ {
+			combination = append(combination, i)
+			if !build(i + 1) {
+				return false
+			}
+			combination = combination[:len(combination)-1]
+		}
+		return true
+	}
+	return build(0)
+}
+
+func remaining(req types.Requirement) int {
+	return req.Remaining(DefaultUnits)
+}
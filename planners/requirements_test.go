@@ -0,0 +1,72 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package planners
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"reflect"
+	"testing"
+)
+
+func TestOutstandingNeeds(t *testing.T) {
+	tests := []struct {
+		name     string
+		grade    string
+		minGrade string
+		needs    []Need
+	}{
+		{"completed", "B", "", []Need{}},
+		{"completed above the minimum grade", "B", "C", []Need{}},
+		{"completed below the minimum grade", "D", "C", []Need{{Block: "Major", Rule: "Algorithms", Remaining: 1, Options: []string{"COMPSCI161", "COMPSCI162"}}}},
+	}
+	for _, test := range tests {
+		rule := types.Rule{Label: "Algorithms", Kind: types.CourseRule, Required: 1, Qualifiers: types.Qualifiers{MinGrade: test.minGrade}, Requirements: []types.Requirement{
+			{Required: 1, Options: []string{"COMPSCI161", "COMPSCI162"}, Completed: []string{"COMPSCI161"}},
+		}}
+		student := types.Student{
+			Courses: map[string]types.Course{"COMPSCI161": {Department: "COMPSCI", Number: "161", Units: 4.0, Grade: test.grade}},
+			Taken:   map[string]bool{"COMPSCI161": true},
+			Blocks:  []types.Block{{Title: "Major", Rules: []types.Rule{rule}}},
+		}
+		if needs := OutstandingNeeds(&student); !reflect.DeepEqual(needs, test.needs) {
+			t.Errorf("%v: OutstandingNeeds = %v, want %v", test.name, needs, test.needs)
+		}
+	}
+}
+
+func TestWaivedChildrenAreNotOutstanding(t *testing.T) {
+	group := types.Rule{Label: "Group", Kind: types.GroupRule, Required: 2, Rules: []types.Rule{
+		{Label: "A", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{{Required: 1, Options: []string{"A1"}, Completed: []string{"A1"}}}},
+		{Label: "B", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{{Required: 1, Options: []string{"B1"}}}, Exceptions: []types.Exception{{Kind: types.WaiveException}}},
+	}}
+	student := coverStudent(group)
+	student.Courses["A1"] = types.Course{Units: 4.0, Grade: "A"}
+	student.Taken["A1"] = true
+	if !group.IsCompleted(&student) {
+		t.Fatalf("IsCompleted = false, want true")
+	}
+	if needs := OutstandingNeeds(&student); len(needs) != 0 {
+		t.Errorf("OutstandingNeeds = %v, want none", needs)
+	}
+	if result := MinimumCourseSet(&student); result.Size != 0 {
+		t.Errorf("MinimumCourseSet = %v, want no courses", result.Sets)
+	}
+}
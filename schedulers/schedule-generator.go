@@ -0,0 +1,215 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package schedulers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+/* Weekly Schedule Generator */
+
+type Section struct {
+	Course string      `json:"course"`
+	Class  types.Class `json:"class"`
+}
+
+type Schedule struct {
+	Sections []Section `json:"sections"`
+}
+
+func overlaps(a types.Class, b types.Class) bool {
+	if a.Time.IsZero() || b.Time.IsZero() {
+		return false
+	}
+	for _, day := range a.Days {
+		if b.MeetsOn(day) {
+			startA, endA := clockMinutes(a.Time)
+			startB, endB := clockMinutes(b.Time)
+			if (startA < endB) && (startB < endA) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func clockMinutes(t types.Time) (int, int) {
+	start := t.Start.Hour()*60 + t.Start.Minute()
+	end := t.End.Hour()*60 + t.End.Minute()
+	return start, end
+}
+
+func (schedule Schedule) Conflicts(class types.Class) bool {
+	for _, section := range schedule.Sections {
+		if overlaps(section.Class, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// Days returns the number of distinct weekdays with at least one meeting.
+func (schedule Schedule) Days() int {
+	days := make(map[time.Weekday]bool, 0)
+	for _, section := range schedule.Sections {
+		if section.Class.Time.IsZero() {
+			continue
+		}
+		for _, day := range section.Class.Days {
+			days[day] = true
+		}
+	}
+	return len(days)
+}
+
+// Gaps returns the total idle time between consecutive meetings on the same day.
+func (schedule Schedule) Gaps() time.Duration {
+	meetings := make(map[time.Weekday][][2]int, 0)
+	for _, section := range schedule.Sections {
+		if section.Class.Time.IsZero() {
+			continue
+		}
+		start, end := clockMinutes(section.Class.Time)
+		for _, day := range section.Class.Days {
+			meetings[day] = append(meetings[day], [2]int{start, end})
+		}
+	}
+	gaps := 0
+	for _, ms := range meetings {
+		sort.Slice(ms, func(i, j int) bool { return ms[i][0] < ms[j][0] })
+		for i := 1; i < len(ms); i++ {
+			if ms[i][0] > ms[i-1][1] {
+				gaps += ms[i][0] - ms[i-1][1]
+			}
+		}
+	}
+	return time.Duration(gaps) * time.Minute
+}
+
+type Schedules []Schedule
+
+func (slice Schedules) Len() int {
+	return len(slice)
+}
+
+func (slice Schedules) Less(i, j int) bool {
+	if slice[i].Days() != slice[j].Days() {
+		return slice[i].Days() < slice[j].Days()
+	}
+	return slice[i].Gaps() < slice[j].Gaps()
+}
+
+func (slice Schedules) Swap(i, j int) {
+	slice[i], slice[j] = slice[j], slice[i]
+}
+
+type slot struct {
+	course  string
+	bundles [][]types.Class
+}
+
+// slots returns one slot per desired course, each of which must be filled by
+// one enrollable bundle of sections (e.g. a `Lec` with one of its `Dis`).
+func slots(courses map[string]types.Course, yearTerm string, keys []string) ([]slot, error) {
+	result := make([]slot, 0)
+	for _, key := range keys {
+		key = strings.Replace(strings.ToUpper(key), " ", "", -1)
+		course, ok := courses[key]
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("ERROR: Unable to generate schedules. Unknown course `%v`.", key))
+		}
+		bundles := make([][]types.Class, 0)
+		for _, bundle := range course.ClassBundles(yearTerm) {
+			if !conflicting(bundle) {
+				bundles = append(bundles, bundle)
+			}
+		}
+		if len(bundles) == 0 {
+			return nil, errors.New(fmt.Sprintf("ERROR: Unable to generate schedules. %v %v is not offered in term %v.", course.Department, course.Number, yearTerm))
+		}
+		result = append(result, slot{course: key, bundles: bundles})
+	}
+	return result, nil
+}
+
+func conflicting(bundle []types.Class) bool {
+	for i := range bundle {
+		for j := i + 1; j < len(bundle); j++ {
+			if overlaps(bundle[i], bundle[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (schedule Schedule) ConflictsWith(bundle []types.Class) bool {
+	for _, class := range bundle {
+		if schedule.Conflicts(class) {
+			return true
+		}
+	}
+	return false
+}
+
+func withBundle(sections []Section, course string, bundle []types.Class) []Section {
+	for _, class := range bundle {
+		sections = append(sections, Section{Course: course, Class: class})
+	}
+	return sections
+}
+
+// GenerateSchedules lists every conflict-free schedule, fewest days first.
+// The CLI ranks with TopSchedules instead; this exhaustive search is kept as
+// the reference TopSchedules is tested against.
+func GenerateSchedules(courses map[string]types.Course, yearTerm string, keys []string) (Schedules, error) {
+	ss, err := slots(courses, yearTerm, keys)
+	if err != nil {
+		return nil, err
+	}
+	schedules := make(Schedules, 0)
+	sections := make([]Section, 0)
+	var search func(i int)
+	search = func(i int) {
+		if i == len(ss) {
+			schedule := Schedule{Sections: make([]Section, len(sections))}
+			copy(schedule.Sections, sections)
+			schedules = append(schedules, schedule)
+			return
+		}
+		for _, bundle := range ss[i].bundles {
+			if (Schedule{Sections: sections}).ConflictsWith(bundle) {
+				continue
+			}
+			mark := len(sections)
+			sections = withBundle(sections, ss[i].course, bundle)
+			search(i + 1)
+			sections = sections[:mark]
+		}
+	}
+	search(0)
+	sort.Stable(schedules)
+	return schedules, nil
+}
@@ -0,0 +1,194 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package schedulers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+)
+
+/* Preference-Weighted Schedule Scoring */
+
+type Preferences struct {
+	EarliestStart string             `json:"earliestStart"`
+	EarlyWeight   float64            `json:"earlyWeight"`
+	DaysWeight    float64            `json:"daysWeight"`
+	GapsWeight    float64            `json:"gapsWeight"`
+	Instructors   map[string]float64 `json:"instructors"`
+	OpenWeight    float64            `json:"openWeight"`
+}
+
+// DefaultPreferences is used when no preferences are given. It penalizes each
+// day on campus as much as ten hours between classes, so fewer days usually
+// win, but unlike GenerateSchedules it doesn't rank strictly by days first.
+var DefaultPreferences = Preferences{DaysWeight: 10.0, GapsWeight: 1.0}
+
+func PreferencesFromFile(filepath string) (Preferences, error) {
+	fileBytes, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return Preferences{}, err
+	}
+	var prefs Preferences
+	err = json.Unmarshal(fileBytes, &prefs)
+	if err != nil {
+		return Preferences{}, err
+	}
+	if len(prefs.EarliestStart) > 0 {
+		if _, err := time.Parse("15:04", prefs.EarliestStart); err != nil {
+			return Preferences{}, errors.New(fmt.Sprintf("ERROR: Invalid earliest start time `%v`. Expected format: HH:MM.", prefs.EarliestStart))
+		}
+	}
+	return prefs, nil
+}
+
+func (prefs Preferences) earliestStart() int {
+	t, err := time.Parse("15:04", prefs.EarliestStart)
+	if err != nil {
+		return 0
+	}
+	return t.Hour()*60 + t.Minute()
+}
+
+// sectionScore holds the part of the score that depends on a single section,
+// which lets partial schedules be bounded during the search.
+func (prefs Preferences) sectionScore(class types.Class) float64 {
+	score := 0.0
+	if !class.Time.IsZero() && (len(prefs.EarliestStart) > 0) {
+		start, _ := clockMinutes(class.Time)
+		if early := prefs.earliestStart() - start; early > 0 {
+			score -= prefs.EarlyWeight * (float64(early) / 60.0) * float64(len(class.Days))
+		}
+	}
+	instructor := strings.ToUpper(class.Instructor)
+	for name, weight := range prefs.Instructors {
+		if (len(name) > 0) && strings.Contains(instructor, strings.ToUpper(name)) {
+			score += weight
+		}
+	}
+	if class.IsOpen() {
+		score += prefs.OpenWeight
+	}
+	return score
+}
+
+func (prefs Preferences) bundleScore(bundle []types.Class) float64 {
+	score := 0.0
+	for _, class := range bundle {
+		score += prefs.sectionScore(class)
+	}
+	return score
+}
+
+func (prefs Preferences) Score(schedule Schedule) float64 {
+	score := 0.0
+	for _, section := range schedule.Sections {
+		score += prefs.sectionScore(section.Class)
+	}
+	score -= prefs.DaysWeight * float64(schedule.Days())
+	score -= prefs.GapsWeight * schedule.Gaps().Hours()
+	return score
+}
+
+type ScoredSchedule struct {
+	Schedule
+	Score float64 `json:"score"`
+}
+
+type ScoredSchedules []ScoredSchedule
+
+func (slice ScoredSchedules) Len() int {
+	return len(slice)
+}
+
+func (slice ScoredSchedules) Less(i, j int) bool {
+	return slice[i].Score > slice[j].Score
+}
+
+func (slice ScoredSchedules) Swap(i, j int) {
+	slice[i], slice[j] = slice[j], slice[i]
+}
+
+// TopSchedules returns the n best conflict-free schedules under prefs.
+// Branches are pruned once their optimistic bound cannot beat the current
+// n-th best score. Day and gap penalties can only grow (or vanish, for gaps)
+// as sections are added (given non-negative weights), so the bound uses
+// the best bundle score remaining for every unfilled slot and no gap penalty.
+func TopSchedules(courses map[string]types.Course, yearTerm string, keys []string, prefs Preferences, n int) (ScoredSchedules, error) {
+	ss, err := slots(courses, yearTerm, keys)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, errors.New(fmt.Sprintf("ERROR: Unable to rank schedules. Invalid number of schedules: %v.", n))
+	}
+	
+	bestRemaining := make([]float64, len(ss)+1)
+	for i := len(ss) - 1; i >= 0; i-- {
+		bundles := ss[i].bundles
+		sort.SliceStable(bundles, func(a, b int) bool {
+			return prefs.bundleScore(bundles[a]) > prefs.bundleScore(bundles[b])
+		})
+		bestRemaining[i] = bestRemaining[i+1] + prefs.bundleScore(bundles[0])
+	}
+	
+	prunable := (prefs.DaysWeight >= 0.0) && (prefs.GapsWeight >= 0.0)
+	top := make(ScoredSchedules, 0, n+1)
+	sections := make([]Section, 0)
+	var search func(i int, partial float64)
+	search = func(i int, partial float64) {
+		current := Schedule{Sections: sections}
+		if prunable && (len(top) == n) {
+			bound := partial + bestRemaining[i] - prefs.DaysWeight*float64(current.Days())
+			if bound <= top[n-1].Score {
+				return
+			}
+		}
+		if i == len(ss) {
+			schedule := Schedule{Sections: make([]Section, len(sections))}
+			copy(schedule.Sections, sections)
+			scored := ScoredSchedule{Schedule: schedule, Score: prefs.Score(schedule)}
+			j := sort.Search(len(top), func(k int) bool { return top[k].Score < scored.Score })
+			top = append(top, ScoredSchedule{})
+			copy(top[j+1:], top[j:])
+			top[j] = scored
+			if len(top) > n {
+				top = top[:n]
+			}
+			return
+		}
+		for _, bundle := range ss[i].bundles {
+			if current.ConflictsWith(bundle) {
+				continue
+			}
+			mark := len(sections)
+			sections = withBundle(sections, ss[i].course, bundle)
+			search(i+1, partial+prefs.bundleScore(bundle))
+			sections = sections[:mark]
+		}
+	}
+	search(0, 0.0)
+	return top, nil
+}
@@ -0,0 +1,139 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package schedulers
+
+import (
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"sort"
+	"testing"
+)
+
+const testTerm = "2017-92"
+
+func testClass(code, kind, section, days, time, instructor, status string) types.Class {
+	return types.Class{Code: code, Type: kind, Section: section, Days: types.ParseDays(days), Time: types.ParseTime(time), Instructor: instructor, Status: status}
+}
+
+func testCourses() map[string]types.Course {
+	courses := make(map[string]types.Course, 0)
+	add := func(dept, num string, classes ...types.Class) {
+		course := types.Course{Department: dept, Number: num, Classes: map[string][]types.Class{testTerm: classes}}
+		courses[course.Key()] = course
+	}
+	add("COMPSCI", "161",
+		testClass("34000", "Lec", "A", "MWF", " 9:00- 9:50", "SHINDLER, M.", "OPEN"),
+		testClass("34001", "Lec", "B", "TuTh", " 2:00- 3:20p", "GOODRICH, M.", "FULL"),
+	)
+	add("COMPSCI", "171",
+		testClass("34100", "Lec", "A", "TuTh", " 9:30-10:50", "KASK, K.", "OPEN"),
+		testClass("34101", "Lec", "B", "MWF", "11:00-11:50", "SMYTH, P.", "OPEN"),
+		testClass("34102", "Lec", "C", "TuTh", " 2:00- 3:20p", "IHLER, A.", "OPEN"),
+	)
+	add("I&C SCI", "139W",
+		testClass("35000", "Lec", "A", "MW", " 8:00- 9:20", "ALFARO, C.", "OPEN"),
+		testClass("35001", "Lec", "B", "TuTh", "12:30- 1:50p", "ALFARO, C.", "OPEN"),
+		testClass("35002", "Lec", "C", "F", " 1:00- 3:50p", "ALFARO, C.", "FULL"),
+	)
+	return courses
+}
+
+func sectionCodes(schedule Schedule) string {
+	codes := make([]string, 0)
+	for _, section := range schedule.Sections {
+		codes = append(codes, section.Class.Code)
+	}
+	sort.Strings(codes)
+	return fmt.Sprint(codes)
+}
+
+func TestTopSchedulesMatchesExhaustiveSearch(t *testing.T) {
+	courses := testCourses()
+	keys := []string{"COMPSCI161", "COMPSCI171", "I&CSCI139W"}
+	all, err := GenerateSchedules(courses, testTerm, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		prefs Preferences
+		n     int
+	}{
+		{"default", DefaultPreferences, 3},
+		{"early riser", Preferences{EarliestStart: "10:00", EarlyWeight: 5.0, DaysWeight: 1.0}, 2},
+		{"instructor", Preferences{Instructors: map[string]float64{"IHLER": 20.0, "SHINDLER": -20.0}, GapsWeight: 1.0}, 4},
+		{"open sections", Preferences{OpenWeight: 3.0, DaysWeight: 2.0, GapsWeight: 0.5}, 5},
+		{"more than exist", DefaultPreferences, 100},
+	}
+	for _, test := range tests {
+		want := make([]float64, 0)
+		for _, schedule := range all {
+			want = append(want, test.prefs.Score(schedule))
+		}
+		sort.Sort(sort.Reverse(sort.Float64Slice(want)))
+		if len(want) > test.n {
+			want = want[:test.n]
+		}
+		got, err := TopSchedules(courses, testTerm, keys, test.prefs, test.n)
+		if err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%v: got %d schedules, want %d", test.name, len(got), len(want))
+		}
+		seen := make(map[string]bool, 0)
+		for i := range got {
+			if got[i].Score != want[i] {
+				t.Errorf("%v: schedule %d scores %v, want %v", test.name, i, got[i].Score, want[i])
+			}
+			codes := sectionCodes(got[i].Schedule)
+			if seen[codes] {
+				t.Errorf("%v: schedule %v returned twice", test.name, codes)
+			}
+			seen[codes] = true
+		}
+	}
+}
+
+func TestGenerateSchedulesHasNoConflicts(t *testing.T) {
+	courses := testCourses()
+	schedules, err := GenerateSchedules(courses, testTerm, []string{"COMPSCI161", "COMPSCI171", "I&CSCI139W"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedules) == 0 {
+		t.Fatal("expected at least one schedule")
+	}
+	for _, schedule := range schedules {
+		for i, a := range schedule.Sections {
+			for _, b := range schedule.Sections[i+1:] {
+				if overlaps(a.Class, b.Class) {
+					t.Errorf("schedule %v has overlapping sections %v and %v", sectionCodes(schedule), a.Class.Code, b.Class.Code)
+				}
+			}
+		}
+	}
+}
+
+func TestTopSchedulesRejectsInvalidCount(t *testing.T) {
+	if _, err := TopSchedules(testCourses(), testTerm, []string{"COMPSCI161"}, DefaultPreferences, 0); err == nil {
+		t.Error("expected an error for n = 0")
+	}
+}
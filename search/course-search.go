@@ -0,0 +1,278 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package search
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+/* Course Search */
+
+const (
+	LevelLower    = "lower"
+	LevelUpper    = "upper"
+	LevelGraduate = "graduate"
+)
+
+type Query struct {
+	Keywords   string         `json:"keywords"`
+	Department string         `json:"department"`
+	Level      string         `json:"level"`
+	MinUnits   float64        `json:"minUnits"`
+	MaxUnits   float64        `json:"maxUnits"`
+	GE         string         `json:"ge"`
+	Term       string         `json:"term"`
+	Days       []time.Weekday `json:"days"`
+	After      string         `json:"after"`
+	Before     string         `json:"before"`
+	Instructor string         `json:"instructor"`
+	Cleared    bool           `json:"cleared"`
+}
+
+type Result struct {
+	Key    string       `json:"key"`
+	Course types.Course `json:"course"`
+	Score  float64      `json:"score"`
+}
+
+type Results []Result
+
+func (slice Results) Len() int {
+	return len(slice)
+}
+
+func (slice Results) Less(i, j int) bool {
+	if slice[i].Score != slice[j].Score {
+		return slice[i].Score > slice[j].Score
+	}
+	return slice[i].Key < slice[j].Key
+}
+
+func (slice Results) Swap(i, j int) {
+	slice[i], slice[j] = slice[j], slice[i]
+}
+
+var fieldWeights = []float64{3.0, 2.0, 1.0}
+
+func fields(course types.Course) []string {
+	return []string{course.Title, course.ShortTitle, course.Description}
+}
+
+var tokenizer = regexp.MustCompile(`[a-z0-9]+`)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"the": true, "to": true, "with": true, "course": true, "courses": true, "students": true,
+}
+
+func Tokenize(text string) []string {
+	tokens := make([]string, 0)
+	for _, token := range tokenizer.FindAllString(strings.ToLower(text), -1) {
+		if !stopWords[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func clockMinutes(hhmm string) (int, bool) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func (query Query) matchesLevel(course types.Course) bool {
+	level := course.Level()
+	switch query.Level {
+	case LevelLower:
+		return level < 100
+	case LevelUpper:
+		return (100 <= level) && (level < 200)
+	case LevelGraduate:
+		return level >= 200
+	}
+	return true
+}
+
+func (query Query) matchesGE(course types.Course) bool {
+	if len(query.GE) == 0 {
+		return true
+	}
+	for _, ge := range course.GE {
+		if strings.EqualFold(ge, query.GE) || strings.EqualFold(strings.TrimRight(ge, "ab"), query.GE) {
+			return true
+		}
+	}
+	return false
+}
+
+func (query Query) matchesClass(class types.Class) bool {
+	if class.Time.IsZero() {
+		return (len(query.Days) == 0) && (len(query.After) == 0) && (len(query.Before) == 0)
+	}
+	for _, day := range class.Days {
+		if (len(query.Days) > 0) && !(types.Class{Days: query.Days}).MeetsOn(day) {
+			return false
+		}
+	}
+	start := class.Time.Start.Hour()*60 + class.Time.Start.Minute()
+	end := class.Time.End.Hour()*60 + class.Time.End.Minute()
+	if after, ok := clockMinutes(query.After); ok && (start < after) {
+		return false
+	}
+	if before, ok := clockMinutes(query.Before); ok && (end > before) {
+		return false
+	}
+	return true
+}
+
+// matchesSections reports whether any enrollable bundle of sections fits the
+// day and time window and, if given, is taught by the instructor.
+func (query Query) matchesSections(course types.Course) bool {
+	if (len(query.Days) == 0) && (len(query.After) == 0) && (len(query.Before) == 0) && (len(query.Instructor) == 0) {
+		return true
+	}
+	for _, bundle := range course.ClassBundles(query.Term) {
+		fits := true
+		taught := len(query.Instructor) == 0
+		for _, class := range bundle {
+			if !query.matchesClass(class) {
+				fits = false
+				break
+			}
+			if strings.Contains(strings.ToUpper(class.Instructor), strings.ToUpper(query.Instructor)) {
+				taught = true
+			}
+		}
+		if fits && taught {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeDepartment(department string) string {
+	return strings.Replace(strings.ToUpper(department), " ", "", -1)
+}
+
+// Matches reports whether the course passes every filter of the query. When
+// the term's schedule is one of the loaded terms, only courses with classes in
+// it are offered; otherwise the offering history over the loaded terms has to
+// make it likely.
+func (query Query) Matches(course types.Course, student *types.Student, terms []string) bool {
+	if (len(query.Department) > 0) && (normalizeDepartment(course.Department) != normalizeDepartment(query.Department)) {
+		return false
+	}
+	if !query.matchesLevel(course) || !query.matchesGE(course) {
+		return false
+	}
+	if (query.MinUnits > 0.0) && (course.Units < query.MinUnits) {
+		return false
+	}
+	if (query.MaxUnits > 0.0) && (course.Units > query.MaxUnits) {
+		return false
+	}
+	if len(query.Term) > 0 {
+		scheduled := false
+		for _, term := range terms {
+			if term == query.Term {
+				scheduled = true
+			}
+		}
+		if scheduled {
+			if len(course.Classes[query.Term]) == 0 {
+				return false
+			}
+		} else if !course.PredictOffering(query.Term, terms).Likely() {
+			return false
+		}
+		if !query.matchesSections(course) {
+			return false
+		}
+	}
+	if query.Cleared && ((student == nil) || !course.ClearedPrereqs(student)) {
+		return false
+	}
+	return true
+}
+
+// Search returns the courses that match every filter of the query. Keyword
+// results are ranked by TF-IDF, weighing matches in the title above matches
+// in the short title and description. Section filters without a term apply
+// to the current term as of the clock.
+func Search(catalogue *types.Catalogue, student *types.Student, query Query, clock types.Clock) Results {
+	filtersSections := (len(query.Days) > 0) || (len(query.After) > 0) || (len(query.Before) > 0) || (len(query.Instructor) > 0)
+	if (len(query.Term) == 0) && filtersSections && (len(catalogue.Terms) > 0) {
+		query.Term = types.CurrentTerm(catalogue.Terms, clock)
+	}
+	
+	keywords := Tokenize(query.Keywords)
+	df := make(map[string]int, 0)
+	if len(keywords) > 0 {
+		for _, course := range catalogue.Courses {
+			seen := make(map[string]bool, 0)
+			for _, field := range fields(course) {
+				for _, token := range Tokenize(field) {
+					seen[token] = true
+				}
+			}
+			for token := range seen {
+				df[token]++
+			}
+		}
+	}
+	n := float64(len(catalogue.Courses))
+	
+	results := make(Results, 0)
+	for key, course := range catalogue.Courses {
+		if !query.Matches(course, student, catalogue.Terms) {
+			continue
+		}
+		score := 0.0
+		if len(keywords) > 0 {
+			for i, field := range fields(course) {
+				tf := make(map[string]int, 0)
+				for _, token := range Tokenize(field) {
+					tf[token]++
+				}
+				for _, keyword := range keywords {
+					if tf[keyword] > 0 {
+						idf := math.Log(n / float64(df[keyword])) + 1.0
+						score += fieldWeights[i] * (1.0 + math.Log(float64(tf[keyword]))) * idf
+					}
+				}
+			}
+			if score == 0.0 {
+				continue
+			}
+		}
+		results = append(results, Result{Key: key, Course: course, Score: score})
+	}
+	sort.Sort(results)
+	return results
+}
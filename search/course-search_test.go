@@ -0,0 +1,88 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package search
+
+import (
+	"github.com/nicolasgomollon/peterplanner/types"
+	"testing"
+)
+
+func TestQueryMatches(t *testing.T) {
+	fallOnly := map[string][]int{"F": {2014, 2015, 2016, 2017}}
+	tests := []struct {
+		name      string
+		query     Query
+		course    types.Course
+		terms     []string
+		expected  bool
+	}{
+		{"department without spaces", Query{Department: "i&c sci"}, types.Course{Department: "I&CSCI", Number: "32"}, nil, true},
+		{"department with spaces", Query{Department: "I&CSCI"}, types.Course{Department: "I&C SCI", Number: "32"}, nil, true},
+		{"other department", Query{Department: "COMPSCI"}, types.Course{Department: "I&C SCI", Number: "32"}, nil, false},
+		{"no history", Query{Term: "2018-92"}, types.Course{Department: "COMPSCI", Number: "161"}, nil, false},
+		{"likely from history", Query{Term: "2018-92"}, types.Course{Department: "COMPSCI", Number: "161", Offered: fallOnly}, nil, true},
+		{"not offered again in the loaded terms", Query{Term: "2018-92"}, types.Course{Department: "COMPSCI", Number: "161", Offered: map[string][]int{"F": {2014}}}, []string{"2014-92", "2018-14"}, false},
+		{"unlikely from history", Query{Term: "2019-03"}, types.Course{Department: "COMPSCI", Number: "161", Offered: fallOnly}, nil, false},
+		{"scheduled without classes", Query{Term: "2018-92"}, types.Course{Department: "COMPSCI", Number: "161", Offered: fallOnly}, []string{"2018-92"}, false},
+		{"scheduled with classes", Query{Term: "2018-92"}, types.Course{Department: "COMPSCI", Number: "161", Classes: map[string][]types.Class{"2018-92": {{Code: "34000", Type: "Lec"}}}}, []string{"2018-92"}, true},
+		{"upper division", Query{Level: LevelUpper}, types.Course{Department: "COMPSCI", Number: "161"}, nil, true},
+		{"lower division", Query{Level: LevelUpper}, types.Course{Department: "COMPSCI", Number: "21"}, nil, false},
+	}
+	for _, test := range tests {
+		if matches := test.query.Matches(test.course, nil, test.terms); matches != test.expected {
+			t.Errorf("%v: Matches = %v, want %v", test.name, matches, test.expected)
+		}
+	}
+}
+
+func TestSearchRanksTitleMatchesFirst(t *testing.T) {
+	catalogue := types.Catalogue{Courses: map[string]types.Course{
+		"COMPSCI171": {Department: "COMPSCI", Number: "171", Title: "Introduction to Artificial Intelligence", Description: "Search, learning and reasoning."},
+		"COMPSCI178": {Department: "COMPSCI", Number: "178", Title: "Machine Learning and Data-Mining", Description: "Introduction to learning from data."},
+		"COMPSCI161": {Department: "COMPSCI", Number: "161", Title: "Design and Analysis of Algorithms", Description: "Techniques for efficient algorithms."},
+	}}
+	results := Search(&catalogue, nil, Query{Keywords: "learning"}, types.SystemClock{})
+	if (len(results) != 2) || (results[0].Key != "COMPSCI178") || (results[1].Key != "COMPSCI171") {
+		t.Errorf("Search(learning) = %v, want COMPSCI178 then COMPSCI171", results)
+	}
+}
+
+func TestSearchDefaultsToTheCurrentTerm(t *testing.T) {
+	catalogue := types.Catalogue{Terms: []string{"2017-92", "2018-03"}, Courses: map[string]types.Course{
+		"COMPSCI161": {Department: "COMPSCI", Number: "161", Classes: map[string][]types.Class{"2017-92": {{Code: "34000", Type: "Lec", Instructor: "SHINDLER, M."}}}},
+	}}
+	tests := []struct {
+		date    string
+		matches int
+	}{
+		{"2017-10-15", 1},
+		{"2018-02-01", 0},
+	}
+	for _, test := range tests {
+		clock, err := types.ParseAsOf(test.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if results := Search(&catalogue, nil, Query{Instructor: "shindler"}, clock); len(results) != test.matches {
+			t.Errorf("as of %v: %d results, want %d", test.date, len(results), test.matches)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package search
+
+import (
+	"github.com/nicolasgomollon/peterplanner/planners"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"math"
+	"sort"
+)
+
+/* Course Similarity Index */
+
+// SimilarPerCourse is the number of most similar courses kept for each course.
+const SimilarPerCourse = 25
+
+type vector map[string]float64
+
+// vectors builds L2-normalized TF-IDF vectors over each course's title and
+// description. Titles are counted twice since they are the densest signal.
+func vectors(courses map[string]types.Course) map[string]vector {
+	counts := make(map[string]map[string]int, 0)
+	df := make(map[string]int, 0)
+	for key, course := range courses {
+		tf := make(map[string]int, 0)
+		title := course.Title
+		if len(title) == 0 {
+			title = course.ShortTitle
+		}
+		for _, token := range Tokenize(title + " " + title + " " + course.Description) {
+			tf[token]++
+		}
+		for token := range tf {
+			df[token]++
+		}
+		counts[key] = tf
+	}
+	n := float64(len(courses))
+	vs := make(map[string]vector, 0)
+	for key, tf := range counts {
+		v := make(vector, 0)
+		norm := 0.0
+		for token, count := range tf {
+			w := (1.0 + math.Log(float64(count))) * math.Log(n / float64(df[token]))
+			if w > 0.0 {
+				v[token] = w
+				norm += w * w
+			}
+		}
+		norm = math.Sqrt(norm)
+		for token := range v {
+			v[token] /= norm
+		}
+		vs[key] = v
+	}
+	return vs
+}
+
+// BuildSimilarityIndex computes, for every course, the most similar courses by
+// cosine similarity of their description vectors. It runs entirely offline,
+// and is stored on its own and loaded into `Catalogue.Similar` with the
+// catalogue.
+func BuildSimilarityIndex(courses map[string]types.Course) map[string][]types.SimilarCourse {
+	vs := vectors(courses)
+	postings := make(map[string][]string, 0)
+	for key, v := range vs {
+		for token := range v {
+			postings[token] = append(postings[token], key)
+		}
+	}
+	index := make(map[string][]types.SimilarCourse, 0)
+	for key, v := range vs {
+		scores := make(map[string]float64, 0)
+		for token, w := range v {
+			for _, other := range postings[token] {
+				if other != key {
+					scores[other] += w * vs[other][token]
+				}
+			}
+		}
+		similar := make([]types.SimilarCourse, 0, len(scores))
+		for other, score := range scores {
+			similar = append(similar, types.SimilarCourse{Key: other, Score: math.Round(score*1000.0) / 1000.0})
+		}
+		sort.Slice(similar, func(i, j int) bool {
+			if similar[i].Score != similar[j].Score {
+				return similar[i].Score > similar[j].Score
+			}
+			return similar[i].Key < similar[j].Key
+		})
+		if len(similar) > SimilarPerCourse {
+			similar = similar[:SimilarPerCourse]
+		}
+		index[key] = similar
+	}
+	return index
+}
+
+type Suggestion struct {
+	Key         string  `json:"key"`
+	Score       float64 `json:"score"`
+	Requirement string  `json:"requirement"`
+}
+
+// Suggest ranks courses by their similarity to the liked courses. With a
+// student, only untaken options of unfinished requirements are suggested, so
+// every suggestion also counts toward the degree. When no courses are liked,
+// each option is compared with the courses already completed in its
+// requirement instead.
+func Suggest(catalogue *types.Catalogue, student *types.Student, liked []string, n int) []Suggestion {
+	suggestions := make([]Suggestion, 0)
+	if student == nil {
+		scores := make(map[string]float64, 0)
+		for _, key := range liked {
+			for _, similar := range catalogue.Similar[key] {
+				scores[similar.Key] = math.Max(scores[similar.Key], similar.Score)
+			}
+		}
+		for _, key := range liked {
+			delete(scores, key)
+		}
+		for key, score := range scores {
+			suggestions = append(suggestions, Suggestion{Key: key, Score: score})
+		}
+	} else {
+		completedIn := make(map[string][]string, 0)
+		for _, block := range student.Blocks {
+			for _, rule := range block.Flatten(student) {
+				for _, req := range rule.Requirements {
+					completedIn[block.Title + ": " + rule.Label] = append(completedIn[block.Title + ": " + rule.Label], req.Completed...)
+				}
+			}
+		}
+		best := make(map[string]Suggestion, 0)
+		for _, need := range planners.OutstandingNeeds(student) {
+			references := liked
+			if len(references) == 0 {
+				references = completedIn[need.Label()]
+			}
+			for _, option := range need.Options {
+				score := 0.0
+				for _, reference := range references {
+					score = math.Max(score, math.Max(catalogue.Similarity(reference, option), catalogue.Similarity(option, reference)))
+				}
+				if (score > 0.0) && (score > best[option].Score) {
+					best[option] = Suggestion{Key: option, Score: score, Requirement: need.Label()}
+				}
+			}
+		}
+		for _, suggestion := range best {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Key < suggestions[j].Key
+	})
+	if (n > 0) && (len(suggestions) > n) {
+		suggestions = suggestions[:n]
+	}
+	return suggestions
+}
@@ -0,0 +1,121 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package search
+
+import (
+	"fmt"
+	"github.com/nicolasgomollon/peterplanner/types"
+	"reflect"
+	"testing"
+)
+
+func similarityCourses() map[string]types.Course {
+	return map[string]types.Course{
+		"COMPSCI178": {Department: "COMPSCI", Number: "178", Title: "Machine Learning", Description: "Learning from data with neural networks."},
+		"COMPSCI175": {Department: "COMPSCI", Number: "175", Title: "Deep Learning", Description: "Neural networks and learning from data."},
+		"COMPSCI171": {Department: "COMPSCI", Number: "171", Title: "Artificial Intelligence", Description: "Search, planning and learning."},
+		"HISTORY40A": {Department: "HISTORY", Number: "40A", Title: "Medieval History", Description: "The history of medieval Europe."},
+		"HISTORY40B": {Department: "HISTORY", Number: "40B", Title: "Renaissance History", Description: "The history of Renaissance Europe."},
+	}
+}
+
+func TestBuildSimilarityIndex(t *testing.T) {
+	index := BuildSimilarityIndex(similarityCourses())
+	tests := []struct {
+		key     string
+		nearest string
+		absent  []string
+	}{
+		{"COMPSCI178", "COMPSCI175", []string{"COMPSCI178", "HISTORY40A", "HISTORY40B"}},
+		{"HISTORY40A", "HISTORY40B", []string{"HISTORY40A", "COMPSCI171", "COMPSCI175", "COMPSCI178"}},
+	}
+	for _, test := range tests {
+		similar := index[test.key]
+		if (len(similar) == 0) || (similar[0].Key != test.nearest) {
+			t.Errorf("%v: most similar = %v, want %v first", test.key, similar, test.nearest)
+		}
+		for i, s := range similar {
+			if (s.Score <= 0.0) || (s.Score > 1.0) {
+				t.Errorf("%v: %v scored %v, want within (0, 1]", test.key, s.Key, s.Score)
+			}
+			if (i > 0) && (similar[i-1].Score < s.Score) {
+				t.Errorf("%v: %v ranked out of order", test.key, similar)
+			}
+			for _, absent := range test.absent {
+				if s.Key == absent {
+					t.Errorf("%v: %v listed as similar", test.key, absent)
+				}
+			}
+		}
+	}
+	catalogue := types.Catalogue{Similar: index}
+	if a, b := catalogue.Similarity("COMPSCI178", "COMPSCI175"), catalogue.Similarity("COMPSCI175", "COMPSCI178"); a != b {
+		t.Errorf("Similarity is not symmetric: %v and %v", a, b)
+	}
+}
+
+func TestBuildSimilarityIndexKeepsTheMostSimilar(t *testing.T) {
+	courses := map[string]types.Course{}
+	for i := 0; i < SimilarPerCourse+5; i++ {
+		courses[fmt.Sprintf("COMPSCI%d", 100+i)] = types.Course{Title: "Special Topics", Description: fmt.Sprintf("Topic %d.", i)}
+	}
+	courses["MATH2A"] = types.Course{Title: "Calculus", Description: "Limits and derivatives."}
+	for key, similar := range BuildSimilarityIndex(courses) {
+		if (key != "MATH2A") && (len(similar) != SimilarPerCourse) {
+			t.Errorf("%v: %d similar courses, want %d", key, len(similar), SimilarPerCourse)
+		} else if (key == "MATH2A") && (len(similar) != 0) {
+			t.Errorf("%v: similar to %v, want none", key, similar)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	catalogue := types.Catalogue{Courses: similarityCourses(), Similar: BuildSimilarityIndex(similarityCourses())}
+	keys := func(suggestions []Suggestion) []string {
+		result := make([]string, 0)
+		for _, suggestion := range suggestions {
+			result = append(result, suggestion.Key)
+		}
+		return result
+	}
+	
+	if suggested := keys(Suggest(&catalogue, nil, []string{"COMPSCI178"}, 2)); !reflect.DeepEqual(suggested, []string{"COMPSCI175", "COMPSCI171"}) {
+		t.Errorf("Suggest without a student = %v, want [COMPSCI175 COMPSCI171]", suggested)
+	}
+	
+	rule := types.Rule{Label: "Electives", Kind: types.CourseRule, Required: 1, Requirements: []types.Requirement{
+		{Required: 1, Options: []string{"COMPSCI175", "HISTORY40B"}, Completed: []string{}},
+		{Required: 1, Options: []string{"COMPSCI178"}, Completed: []string{"COMPSCI178"}},
+	}}
+	rule.Required = 2
+	student := types.Student{
+		Courses: map[string]types.Course{"COMPSCI178": {Department: "COMPSCI", Number: "178", Units: 4.0, Grade: "A"}},
+		Taken:   map[string]bool{"COMPSCI178": true},
+		Blocks:  []types.Block{{Title: "Major", Rules: []types.Rule{rule}}},
+	}
+	// Without liked courses, options are compared with the completed ones.
+	suggestions := Suggest(&catalogue, &student, nil, 0)
+	if (len(suggestions) != 1) || (suggestions[0].Key != "COMPSCI175") || (suggestions[0].Requirement != "Major: Electives") {
+		t.Errorf("Suggest for the student = %+v, want COMPSCI175 for Major: Electives", suggestions)
+	}
+	if suggested := keys(Suggest(&catalogue, &student, []string{"HISTORY40A"}, 0)); !reflect.DeepEqual(suggested, []string{"HISTORY40B"}) {
+		t.Errorf("Suggest for the student liking HISTORY40A = %v, want [HISTORY40B]", suggested)
+	}
+}
@@ -0,0 +1,98 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"strings"
+)
+
+/* DegreeWorks Audit Header */
+
+type AuditInfo struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Date string `json:"date"`
+}
+
+type Degree struct {
+	Code        string `json:"code"`
+	Literal     string `json:"literal"`
+	School      string `json:"school"`
+	Level       string `json:"level"`
+	CatalogYear string `json:"catalogYear"`
+	ActiveTerm  string `json:"activeTerm"`
+}
+
+type Advisor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Type  string `json:"type"`
+}
+
+type Note struct {
+	Type string `json:"type"`
+	Who  string `json:"who"`
+	Date string `json:"date"`
+	Text string `json:"text"`
+}
+
+type Hold struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+func (student Student) goals(codes ...string) []Goal {
+	goals := make([]Goal, 0)
+	for _, goal := range student.Goals {
+		for _, code := range codes {
+			if strings.EqualFold(goal.Code, code) {
+				goals = append(goals, goal)
+				break
+			}
+		}
+	}
+	return goals
+}
+
+func (student Student) Majors() []Goal {
+	return student.goals("MAJOR")
+}
+
+func (student Student) Minors() []Goal {
+	return student.goals("MINOR")
+}
+
+func (student Student) Concentrations() []Goal {
+	return student.goals("CONC", "SPEC")
+}
+
+// CatalogYear is the catalog year that decides which requirements apply,
+// taken from the degree or, failing that, from the first goal that has one.
+func (student Student) CatalogYear() string {
+	if len(student.Degree.CatalogYear) > 0 {
+		return student.Degree.CatalogYear
+	}
+	for _, goal := range student.Goals {
+		if len(goal.CatalogYear) > 0 {
+			return goal.CatalogYear
+		}
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"time"
+	_ "time/tzdata"
+)
+
+/* Academic Calendar */
+
+const DateFormat = "2006-01-02"
+
+var Location = pacificTime()
+
+func pacificTime() *time.Location {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+type Date struct {
+	time.Time
+}
+
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{Time: time.Date(year, month, day, 0, 0, 0, 0, Location)}
+}
+
+func (date Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(date.Format(DateFormat))
+}
+
+func (date *Date) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	t, err := time.ParseInLocation(DateFormat, raw, Location)
+	if err != nil {
+		return errors.New(fmt.Sprintf("ERROR: Invalid calendar date `%v`. Expected format: YYYY-MM-DD.", raw))
+	}
+	date.Time = t
+	return nil
+}
+
+func (date Date) SameDay(t time.Time) bool {
+	t = t.In(Location)
+	return (date.Year() == t.Year()) && (date.Month() == t.Month()) && (date.Day() == t.Day())
+}
+
+type TermDates struct {
+	InstructionStart Date `json:"instructionStart"`
+	InstructionEnd   Date `json:"instructionEnd"`
+	FinalsStart      Date `json:"finalsStart"`
+	FinalsEnd        Date `json:"finalsEnd"`
+}
+
+func (term TermDates) IsInstruction(t time.Time) bool {
+	return !t.Before(term.InstructionStart.Time) && t.Before(term.InstructionEnd.AddDate(0, 0, 1))
+}
+
+func (term TermDates) IsFinalsWeek(t time.Time) bool {
+	return !t.Before(term.FinalsStart.Time) && t.Before(term.FinalsEnd.AddDate(0, 0, 1))
+}
+
+type Holiday struct {
+	Date Date   `json:"date"`
+	Name string `json:"name"`
+}
+
+type Calendar struct {
+	Terms    map[string]TermDates `json:"terms"`
+	Holidays []Holiday            `json:"holidays"`
+}
+
+func CalendarFromFile(filepath string) (Calendar, error) {
+	fileBytes, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return Calendar{}, err
+	}
+	var calendar Calendar
+	err = json.Unmarshal(fileBytes, &calendar)
+	if err != nil {
+		return Calendar{}, err
+	}
+	return calendar, nil
+}
+
+func (calendar Calendar) TermDates(yearTerm string) (TermDates, bool) {
+	term, ok := calendar.Terms[yearTerm]
+	return term, ok
+}
+
+func (calendar Calendar) Holiday(t time.Time) (Holiday, bool) {
+	for _, holiday := range calendar.Holidays {
+		if holiday.Date.SameDay(t) {
+			return holiday, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// On anchors the clock times parsed by ParseTime to the given day.
+func (t Time) On(date time.Time) Time {
+	date = date.In(Location)
+	start := time.Date(date.Year(), date.Month(), date.Day(), t.Start.Hour(), t.Start.Minute(), 0, 0, Location)
+	end := time.Date(date.Year(), date.Month(), date.Day(), t.End.Hour(), t.End.Minute(), 0, 0, Location)
+	return Time{Start: start, End: end}
+}
+
+func (t Time) IsZero() bool {
+	return t.Start.IsZero() && t.End.IsZero()
+}
+
+func (class Class) MeetsOn(day time.Weekday) bool {
+	for _, d := range class.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Occurrences expands a class meeting pattern into every dated meeting
+// during instruction for the given term, skipping campus holidays.
+func (calendar Calendar) Occurrences(class Class, yearTerm string) []Time {
+	occurrences := make([]Time, 0)
+	term, ok := calendar.TermDates(yearTerm)
+	if !ok || (len(class.Days) == 0) || class.Time.IsZero() {
+		return occurrences
+	}
+	for day := term.InstructionStart.Time; term.IsInstruction(day); day = day.AddDate(0, 0, 1) {
+		if !class.MeetsOn(day.Weekday()) {
+			continue
+		}
+		if _, isHoliday := calendar.Holiday(day); isHoliday {
+			continue
+		}
+		occurrences = append(occurrences, class.Time.On(day))
+	}
+	return occurrences
+}
+
+// ParseFinal resolves a WebSOC final exam string, e.g. `Mon, Dec 11, 8:00-10:00am`,
+// to a dated time using the year in which the term's finals week falls.
+func ParseFinal(cFinal string, term TermDates) (Time, bool) {
+	r, _ := regexp.Compile(`([A-Z][a-z]{2})\s+(\d{1,2}),?\s+(\d{1,2}):(\d{2})\s*-\s*(\d{1,2}):(\d{2})\s*(am|pm)`)
+	matches := r.FindStringSubmatch(cFinal)
+	if len(matches) == 0 {
+		return Time{}, false
+	}
+	month, err := time.Parse("Jan", matches[1])
+	if err != nil {
+		return Time{}, false
+	}
+	day, _ := strconv.Atoi(matches[2])
+	startHour, _ := strconv.Atoi(matches[3])
+	startMinute, _ := strconv.Atoi(matches[4])
+	endHour, _ := strconv.Atoi(matches[5])
+	endMinute, _ := strconv.Atoi(matches[6])
+	
+	if (matches[7] == "pm") && (endHour < 12) {
+		endHour += 12
+	}
+	if (startHour < 12) && ((startHour + 12)*60 + startMinute < endHour*60 + endMinute) {
+		startHour += 12
+	}
+	
+	year := term.FinalsStart.Year()
+	start := time.Date(year, month.Month(), day, startHour, startMinute, 0, 0, Location)
+	end := time.Date(year, month.Month(), day, endHour, endMinute, 0, 0, Location)
+	return Time{Start: start, End: end}, true
+}
@@ -0,0 +1,105 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOccurrences(t *testing.T) {
+	calendar := Calendar{
+		Terms: map[string]TermDates{"2017-92": {
+			InstructionStart: NewDate(2017, time.September, 28),
+			InstructionEnd:   NewDate(2017, time.December, 8),
+		}},
+		Holidays: []Holiday{
+			{Date: NewDate(2017, time.November, 10), Name: "Veterans Day"},
+			{Date: NewDate(2017, time.November, 23), Name: "Thanksgiving"},
+			{Date: NewDate(2017, time.November, 24), Name: "Thanksgiving"},
+		},
+	}
+	tests := []struct {
+		name  string
+		days  string
+		time  string
+		term  string
+		count int
+		first string
+		last  string
+	}{
+		{"MWF", "MWF", " 9:00- 9:50", "2017-92", 29, "2017-09-29 09:00", "2017-12-08 09:00"},
+		{"TuTh", "TuTh", " 2:00- 3:20p", "2017-92", 20, "2017-09-28 14:00", "2017-12-07 14:00"},
+		{"no calendar dates", "MWF", " 9:00- 9:50", "2018-03", 0, "", ""},
+		{"no meeting time", "", "", "2017-92", 0, "", ""},
+	}
+	for _, test := range tests {
+		class := Class{Days: ParseDays(test.days)}
+		if len(test.time) > 0 {
+			class.Time = ParseTime(test.time)
+		}
+		occurrences := calendar.Occurrences(class, test.term)
+		if len(occurrences) != test.count {
+			t.Errorf("%v: %d occurrences, want %d", test.name, len(occurrences), test.count)
+			continue
+		}
+		if test.count == 0 {
+			continue
+		}
+		if first := occurrences[0].Start.Format("2006-01-02 15:04"); first != test.first {
+			t.Errorf("%v: first occurrence %v, want %v", test.name, first, test.first)
+		}
+		if last := occurrences[len(occurrences)-1].Start.Format("2006-01-02 15:04"); last != test.last {
+			t.Errorf("%v: last occurrence %v, want %v", test.name, last, test.last)
+		}
+	}
+}
+
+func TestParseFinal(t *testing.T) {
+	term := TermDates{FinalsStart: NewDate(2017, time.December, 9), FinalsEnd: NewDate(2017, time.December, 15)}
+	tests := []struct {
+		final string
+		start string
+		end   string
+		ok    bool
+	}{
+		{"Mon, Dec 11, 8:00-10:00am", "2017-12-11 08:00", "2017-12-11 10:00", true},
+		{"Wed, Dec 13, 1:30-3:30pm", "2017-12-13 13:30", "2017-12-13 15:30", true},
+		{"Thu, Dec 14, 10:30-12:30pm", "2017-12-14 10:30", "2017-12-14 12:30", true},
+		{"TBA", "", "", false},
+	}
+	for _, test := range tests {
+		final, ok := ParseFinal(test.final, term)
+		if ok != test.ok {
+			t.Errorf("ParseFinal(%q) ok = %v, want %v", test.final, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if start := final.Start.Format("2006-01-02 15:04"); start != test.start {
+			t.Errorf("ParseFinal(%q) start = %v, want %v", test.final, start, test.start)
+		}
+		if end := final.End.Format("2006-01-02 15:04"); end != test.end {
+			t.Errorf("ParseFinal(%q) end = %v, want %v", test.final, end, test.end)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+/* Clock */
+
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (clock SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+type FixedClock struct {
+	Time time.Time
+}
+
+func (clock FixedClock) Now() time.Time {
+	return clock.Time
+}
+
+// DefaultClock backs the argument-less helpers such as YearFQ. Code that can
+// be given a date (`-as-of`) should take a Clock instead.
+var DefaultClock Clock = SystemClock{}
+
+func ParseAsOf(date string) (FixedClock, error) {
+	t, err := time.ParseInLocation(DateFormat, date, Location)
+	if err != nil {
+		return FixedClock{}, errors.New(fmt.Sprintf("ERROR: Invalid as-of date `%v`. Expected format: YYYY-MM-DD.", date))
+	}
+	return FixedClock{Time: t}, nil
+}
+
+// TermAsOf returns the quarter in session (or, over the summer, the next one
+// to start) on the given date in campus time. Like YearWQ and YearSQ, the
+// academic year turns over at the end of June.
+func TermAsOf(currentDate time.Time) string {
+	currentDate = currentDate.In(Location)
+	switch month := currentDate.Month(); {
+	case month <= time.March:
+		return WinterQuarter(YearWQAsOf(currentDate))
+	case month <= time.June:
+		return SpringQuarter(YearSQAsOf(currentDate))
+	}
+	return FallQuarter(YearFQAsOf(currentDate))
+}
+
+// CurrentTerm picks the latest of the given terms that isn't after the term
+// in session as of the clock's date. Without a match, the term in session is
+// returned.
+func CurrentTerm(terms []string, clock Clock) string {
+	asOf := TermAsOf(clock.Now())
+	current := ""
+	for _, term := range terms {
+		if (term <= asOf) && (term > current) {
+			current = term
+		}
+	}
+	if len(current) == 0 {
+		return asOf
+	}
+	return current
+}
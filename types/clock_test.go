@@ -0,0 +1,109 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAcademicYearCutoffs(t *testing.T) {
+	tests := []struct {
+		date         string
+		yearFQ       int
+		yearWQ       int
+		yearSQ       int
+		academicYear int
+		term         string
+	}{
+		{"2017-01-09", 2017, 2017, 2017, 2016, "2017-03"},
+		{"2017-04-03", 2017, 2017, 2017, 2016, "2017-14"},
+		{"2017-06-30", 2017, 2017, 2017, 2016, "2017-14"},
+		{"2017-07-01", 2017, 2018, 2018, 2017, "2017-92"},
+		{"2017-09-28", 2017, 2018, 2018, 2017, "2017-92"},
+		{"2017-12-31", 2017, 2018, 2018, 2017, "2017-92"},
+	}
+	for _, test := range tests {
+		clock, err := ParseAsOf(test.date)
+		if err != nil {
+			t.Fatalf("ParseAsOf(%q) returned error: %v", test.date, err)
+		}
+		now := clock.Now()
+		if got := YearFQAsOf(now); got != test.yearFQ {
+			t.Errorf("YearFQAsOf(%v) = %v, want %v", test.date, got, test.yearFQ)
+		}
+		if got := YearWQAsOf(now); got != test.yearWQ {
+			t.Errorf("YearWQAsOf(%v) = %v, want %v", test.date, got, test.yearWQ)
+		}
+		if got := YearSQAsOf(now); got != test.yearSQ {
+			t.Errorf("YearSQAsOf(%v) = %v, want %v", test.date, got, test.yearSQ)
+		}
+		if got := AcademicYearAsOf(now); got != test.academicYear {
+			t.Errorf("AcademicYearAsOf(%v) = %v, want %v", test.date, got, test.academicYear)
+		}
+		if got := TermAsOf(now); got != test.term {
+			t.Errorf("TermAsOf(%v) = %v, want %v", test.date, got, test.term)
+		}
+	}
+}
+
+func TestCutoffUsesCampusTime(t *testing.T) {
+	// 11:30 PM on June 30 in Irvine is already July 1 in UTC.
+	late := time.Date(2017, time.July, 1, 6, 30, 0, 0, time.UTC)
+	if got := AcademicYearAsOf(late); got != 2016 {
+		t.Errorf("AcademicYearAsOf(%v) = %v, want 2016", late, got)
+	}
+	if got := TermAsOf(late); got != "2017-14" {
+		t.Errorf("TermAsOf(%v) = %v, want 2017-14", late, got)
+	}
+}
+
+func TestCurrentTerm(t *testing.T) {
+	terms := []string{"2018-03", "2017-92", "2017-14", "2017-03"}
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2017-02-01", "2017-03"},
+		{"2017-06-30", "2017-14"},
+		{"2017-07-01", "2017-92"},
+		{"2018-05-01", "2018-03"},
+		{"2016-10-01", "2016-92"},
+	}
+	for _, test := range tests {
+		clock, _ := ParseAsOf(test.date)
+		if got := CurrentTerm(terms, clock); got != test.want {
+			t.Errorf("CurrentTerm(%v) = %v, want %v", test.date, got, test.want)
+		}
+	}
+}
+
+func TestParseAsOf(t *testing.T) {
+	if _, err := ParseAsOf("06/30/2017"); err == nil {
+		t.Errorf("ParseAsOf(06/30/2017) should fail")
+	}
+	clock, err := ParseAsOf("2017-06-30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clock.Now().Location() != Location {
+		t.Errorf("ParseAsOf should use the campus time zone, got %v", clock.Now().Location())
+	}
+}
@@ -0,0 +1,134 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+/* DegreeWorks Conditions */
+
+type Goal struct {
+	Code        string `json:"code"`
+	Value       string `json:"value"`
+	Literal     string `json:"literal"`
+	CatalogYear string `json:"catalogYear"`
+}
+
+func (goal Goal) String() string {
+	if len(goal.Literal) > 0 {
+		return goal.Literal
+	}
+	return goal.Value
+}
+
+// Condition is either a single relation (`MAJOR = 201`) or a set of
+// conditions joined by a connector (`AND`/`OR`).
+type Condition struct {
+	Left       string      `json:"left,omitempty"`
+	Operator   string      `json:"operator,omitempty"`
+	Right      string      `json:"right,omitempty"`
+	Connector  string      `json:"connector,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+func (condition Condition) String() string {
+	if len(condition.Conditions) == 0 {
+		return fmt.Sprintf("%v %v %v", condition.Left, condition.Operator, condition.Right)
+	}
+	parts := make([]string, 0)
+	for _, c := range condition.Conditions {
+		if len(c.Conditions) > 1 {
+			parts = append(parts, "(" + c.String() + ")")
+		} else {
+			parts = append(parts, c.String())
+		}
+	}
+	return strings.Join(parts, " " + condition.Connector + " ")
+}
+
+// Evaluate tests the condition against the student's goals. Values on the
+// right-hand side may be a comma-separated list, any of which matches.
+func (condition Condition) Evaluate(goals []Goal) bool {
+	if len(condition.Conditions) > 0 {
+		or := strings.ToUpper(condition.Connector) == "OR"
+		for _, c := range condition.Conditions {
+			result := c.Evaluate(goals)
+			if or && result {
+				return true
+			} else if !or && !result {
+				return false
+			}
+		}
+		return !or
+	}
+	
+	values := make([]string, 0)
+	for _, goal := range goals {
+		if strings.EqualFold(goal.Code, condition.Left) {
+			values = append(values, strings.TrimSpace(goal.Value))
+		}
+	}
+	rights := strings.Split(condition.Right, ",")
+	matches := func(cmp func(value, right string) bool) bool {
+		for _, value := range values {
+			for _, right := range rights {
+				if cmp(value, strings.TrimSpace(right)) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	switch condition.Operator {
+	case "=":
+		return matches(strings.EqualFold)
+	case "<>", "!=":
+		return !matches(strings.EqualFold)
+	case ">":
+		return matches(func(value, right string) bool { return value > right })
+	case ">=":
+		return matches(func(value, right string) bool { return value >= right })
+	case "<":
+		return matches(func(value, right string) bool { return value < right })
+	case "<=":
+		return matches(func(value, right string) bool { return value <= right })
+	}
+	return false
+}
+
+// Conditional is a DegreeWorks `IfStmt` rule. Both branches are kept so that
+// advisors can see what would apply under a different goal; the rules of the
+// active branch are also added to the enclosing block.
+type Conditional struct {
+	Label     string    `json:"label"`
+	Condition Condition `json:"condition"`
+	Then      []Rule    `json:"then"`
+	Else      []Rule    `json:"else"`
+	Satisfied bool      `json:"satisfied"`
+}
+
+func (conditional Conditional) Active() []Rule {
+	if conditional.Satisfied {
+		return conditional.Then
+	}
+	return conditional.Else
+}
@@ -0,0 +1,86 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+/* DegreeWorks Exceptions */
+
+// Exception kinds. Substitutions and Apply Here exceptions count the listed
+// courses toward the rule, Also Allow exceptions add them as options, and
+// Force Complete exceptions waive the rule altogether.
+const (
+	SubstituteException = "Substitute"
+	ApplyHereException  = "ApplyHere"
+	AlsoAllowException  = "AlsoAllow"
+	WaiveException      = "Waive"
+)
+
+var exceptionKinds = map[string]string{
+	"SB": SubstituteException,
+	"AH": ApplyHereException,
+	"AA": AlsoAllowException,
+	"FC": WaiveException,
+}
+
+func ExceptionKind(code string) string {
+	if kind, ok := exceptionKinds[strings.ToUpper(strings.TrimSpace(code))]; ok {
+		return kind
+	}
+	return code
+}
+
+type Exception struct {
+	Kind    string   `json:"kind"`
+	Label   string   `json:"label"`
+	Rule    string   `json:"rule"`
+	Courses []string `json:"courses"`
+	Who     string   `json:"who"`
+	Date    string   `json:"date"`
+}
+
+func (exception Exception) String() string {
+	description := exception.Kind
+	if len(exception.Label) > 0 {
+		description += ": " + exception.Label
+	}
+	if len(exception.Courses) > 0 {
+		description += fmt.Sprintf(" (%v)", strings.Join(exception.Courses, ", "))
+	}
+	if len(exception.Who) > 0 {
+		description += " by " + exception.Who
+	}
+	if len(exception.Date) > 0 {
+		description += " on " + exception.Date
+	}
+	return description
+}
+
+func (rule Rule) IsWaived() bool {
+	for _, exception := range rule.Exceptions {
+		if exception.Kind == WaiveException {
+			return true
+		}
+	}
+	return false
+}
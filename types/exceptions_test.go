@@ -0,0 +1,105 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package types
+
+import (
+	"testing"
+)
+
+func TestExceptionKind(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{"SB", SubstituteException},
+		{"ah", ApplyHereException},
+		{" AA ", AlsoAllowException},
+		{"FC", WaiveException},
+		{"XX", "XX"},
+	}
+	for _, test := range tests {
+		if kind := ExceptionKind(test.code); kind != test.expected {
+			t.Errorf("ExceptionKind(%q) = %v, want %v", test.code, kind, test.expected)
+		}
+	}
+}
+
+func TestWaivedRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		exceptions []Exception
+		waived     bool
+	}{
+		{"no exceptions", []Exception{}, false},
+		{"substitution", []Exception{{Kind: SubstituteException, Courses: []string{"COMPSCI175"}}}, false},
+		{"force complete", []Exception{{Kind: WaiveException}}, true},
+	}
+	for _, test := range tests {
+		rule := Rule{Label: "Algorithms", Kind: CourseRule, Required: 1, Requirements: []Requirement{{Required: 1, Options: []string{"COMPSCI161"}}}, Exceptions: test.exceptions}
+		student := &Student{Courses: map[string]Course{}, Taken: map[string]bool{}}
+		if waived := rule.IsWaived(); waived != test.waived {
+			t.Errorf("%v: IsWaived = %v, want %v", test.name, waived, test.waived)
+		}
+		if completed := rule.IsCompleted(student); completed != test.waived {
+			t.Errorf("%v: IsCompleted = %v, want %v", test.name, completed, test.waived)
+		}
+		if flattened := rule.Flatten(student); (len(flattened) == 0) != test.waived {
+			t.Errorf("%v: Flatten = %v", test.name, flattened)
+		}
+	}
+}
+
+func TestWaivedGroupChildren(t *testing.T) {
+	tests := []struct {
+		name      string
+		required  int
+		completed bool
+	}{
+		{"all children satisfied", 2, true},
+		{"one more needed", 3, false},
+	}
+	for _, test := range tests {
+		rule := Rule{Label: "Group", Kind: GroupRule, Required: test.required, Rules: []Rule{
+			{Label: "A", Kind: CourseRule, Required: 1, Requirements: []Requirement{{Required: 1, Options: []string{"COMPSCI161"}, Completed: []string{"COMPSCI161"}}}},
+			{Label: "B", Kind: CourseRule, Required: 1, Requirements: []Requirement{{Required: 1, Options: []string{"COMPSCI162"}}}, Exceptions: []Exception{{Kind: WaiveException}}},
+			{Label: "C", Kind: CourseRule, Required: 1, Requirements: []Requirement{{Required: 1, Options: []string{"COMPSCI163"}}}},
+		}}
+		student := &Student{
+			Courses: map[string]Course{"COMPSCI161": {Department: "COMPSCI", Number: "161", Units: 4.0, Grade: "A"}},
+			Taken:   map[string]bool{"COMPSCI161": true},
+		}
+		if completed := rule.IsCompleted(student); completed != test.completed {
+			t.Errorf("%v: IsCompleted = %v, want %v", test.name, completed, test.completed)
+		}
+		flattened := rule.Flatten(student)
+		if len(flattened) != 1 {
+			t.Fatalf("%v: Flatten = %v, want one rule", test.name, flattened)
+		}
+		for _, req := range flattened[0].Requirements {
+			if len(req.Options) == 1 && req.Options[0] == "COMPSCI162" {
+				t.Errorf("%v: Flatten kept the waived requirement: %v", test.name, flattened[0].Requirements)
+			}
+		}
+		if completed := flattened[0].IsCompleted(student); completed != test.completed {
+			t.Errorf("%v: flattened IsCompleted = %v, want %v", test.name, completed, test.completed)
+		}
+	}
+}
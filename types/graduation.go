@@ -0,0 +1,96 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"strconv"
+	"unicode"
+)
+
+/* Graduation Estimate */
+
+// DegreeUnits is the minimum number of units required for a bachelor's degree.
+const DegreeUnits = 180.0
+
+type BlockUnits struct {
+	Title          string  `json:"title"`
+	UnitsRemaining float64 `json:"unitsRemaining"`
+}
+
+type Constraint struct {
+	Course  string   `json:"course"`
+	Term    string   `json:"term"`
+	Reasons []string `json:"reasons"`
+}
+
+type GraduationEstimate struct {
+	UnitsRemaining              float64      `json:"unitsRemaining"`
+	UpperDivisionUnitsRemaining float64      `json:"upperDivisionUnitsRemaining"`
+	Blocks                      []BlockUnits `json:"blocks"`
+	Pace                        float64      `json:"pace"`
+	EarliestTerm                string       `json:"earliestTerm"`
+	Constraints                 []Constraint `json:"constraints"`
+}
+
+// Level returns the numeric part of the course number, e.g. 161 for `161A`
+// or 115 for `H115`.
+func (course Course) Level() int {
+	digits := ""
+	for _, r := range course.Number {
+		if unicode.IsDigit(r) {
+			digits += string(r)
+		} else if len(digits) > 0 {
+			break
+		}
+	}
+	level, _ := strconv.Atoi(digits)
+	return level
+}
+
+func (course Course) IsUpperDivision() bool {
+	level := course.Level()
+	return (100 <= level) && (level < 200)
+}
+
+// UpperDivisionCredits sums the units of completed upper-division courses,
+// using the transcript when the audit includes one. Repeated courses count
+// once, with their highest credits.
+func (student Student) UpperDivisionCredits() float64 {
+	credits := 0.0
+	if len(student.Transcript) > 0 {
+		best := make(map[string]float64, 0)
+		for _, entry := range student.Transcript {
+			course := Course{Department: entry.Department, Number: entry.Number}
+			if !entry.InProgress && course.IsUpperDivision() && (entry.Credits > best[entry.Course]) {
+				best[entry.Course] = entry.Credits
+			}
+		}
+		for _, c := range best {
+			credits += c
+		}
+		return credits
+	}
+	for key, course := range student.Courses {
+		if student.Taken[key] && course.IsUpperDivision() {
+			credits += course.Units
+		}
+	}
+	return credits
+}
@@ -0,0 +1,49 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package types
+
+import (
+	"testing"
+)
+
+func TestUpperDivisionCredits(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript Transcript
+		expected   float64
+	}{
+		{"lower division", Transcript{{Course: "COMPSCI21", Department: "COMPSCI", Number: "21", Credits: 4.0}}, 0.0},
+		{"upper division", Transcript{{Course: "COMPSCI161", Department: "COMPSCI", Number: "161", Credits: 4.0}}, 4.0},
+		{"in progress", Transcript{{Course: "COMPSCI161", Department: "COMPSCI", Number: "161", Credits: 4.0, InProgress: true}}, 0.0},
+		{"repeated", Transcript{
+			{Course: "COMPSCI161", Department: "COMPSCI", Number: "161", Term: "2016-92", Grade: "F", Credits: 0.0},
+			{Course: "COMPSCI161", Department: "COMPSCI", Number: "161", Term: "2017-92", Grade: "C", Credits: 4.0, Repeat: true},
+			{Course: "COMPSCI161", Department: "COMPSCI", Number: "161", Term: "2018-03", Grade: "A", Credits: 4.0, Repeat: true},
+		}, 4.0},
+		{"graduate", Transcript{{Course: "COMPSCI261", Department: "COMPSCI", Number: "261", Credits: 4.0}}, 0.0},
+	}
+	for _, test := range tests {
+		student := Student{Transcript: test.transcript}
+		if credits := student.UpperDivisionCredits(); credits != test.expected {
+			t.Errorf("%v: UpperDivisionCredits() = %v, want %v", test.name, credits, test.expected)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/* Offering Predictor */
+
+// OfferingThreshold is the probability above which a course is assumed to be
+// offered in a future term.
+const OfferingThreshold = 0.5
+
+// recencyDecay weighs each earlier year of history less than the one after it.
+const recencyDecay = 0.7
+
+// Forecast is Unknown when the course has no offering history to go by.
+type Forecast struct {
+	Term        string  `json:"term"`
+	Probability float64 `json:"probability"`
+	Confidence  float64 `json:"confidence"`
+	Pattern     string  `json:"pattern"`
+	Unknown     bool    `json:"unknown,omitempty"`
+}
+
+func (forecast Forecast) Likely() bool {
+	return !forecast.Unknown && (forecast.Probability >= OfferingThreshold)
+}
+
+func quarterName(q string) string {
+	switch q {
+	case "F":
+		return "Fall"
+	case "W":
+		return "Winter"
+	case "S":
+		return "Spring"
+	}
+	return q
+}
+
+func termOf(year int, q string) string {
+	switch q {
+	case "F":
+		return FallQuarter(year)
+	case "W":
+		return WinterQuarter(year)
+	}
+	return SpringQuarter(year)
+}
+
+// historyWindow returns the earliest and latest terms the history covers:
+// the span of the loaded terms, widened by any offering outside of it.
+func historyWindow(history map[string][]int, terms []string) (string, string, bool) {
+	first, last := "", ""
+	for _, term := range terms {
+		if !IsAcademicTerm(term) {
+			continue
+		}
+		if (len(first) == 0) || (term < first) {
+			first = term
+		}
+		if term > last {
+			last = term
+		}
+	}
+	for q, years := range history {
+		for _, y := range years {
+			term := termOf(y, q)
+			if (len(first) == 0) || (term < first) {
+				first = term
+			}
+			if term > last {
+				last = term
+			}
+		}
+	}
+	return first, last, len(history) > 0
+}
+
+// observedYears lists the years whose quarter q falls within the history
+// window. Only these terms were loaded, so only they say anything about
+// whether the course is offered in that quarter.
+func observedYears(first string, last string, q string) []int {
+	years := make([]int, 0)
+	from, _ := strconv.Atoi(first[0:4])
+	to, _ := strconv.Atoi(last[0:4])
+	for y := from; y <= to; y++ {
+		if term := termOf(y, q); (first <= term) && (term <= last) {
+			years = append(years, y)
+		}
+	}
+	return years
+}
+
+func sortedYears(years []int) []int {
+	sorted := make([]int, 0)
+	seen := make(map[int]bool, 0)
+	for _, y := range years {
+		if !seen[y] {
+			seen[y] = true
+			sorted = append(sorted, y)
+		}
+	}
+	sort.Ints(sorted)
+	return sorted
+}
+
+// alternating reports whether the years are every other year, e.g. 2013,
+// 2015, 2017, with the observed years spanning at least four years and the
+// gaps in between also observed.
+func alternating(years []int, observed []int) bool {
+	if (len(years) < 2) || (len(observed) < 4) {
+		return false
+	}
+	for i := 1; i < len(years); i++ {
+		if years[i] - years[i-1] != 2 {
+			return false
+		}
+	}
+	return (years[0] - observed[0] <= 1) && (observed[len(observed)-1] - years[len(years)-1] <= 1)
+}
+
+// OfferingPattern describes the historical offering pattern over the loaded
+// terms, e.g. `Fall only` or `Winter, alternate years`.
+func (course Course) OfferingPattern(terms []string) string {
+	history := course.OfferingHistory()
+	first, last, ok := historyWindow(history, terms)
+	if !ok {
+		return "no history"
+	}
+	quarters := make([]string, 0)
+	alternate := false
+	for _, q := range []string{"F", "W", "S"} {
+		if len(history[q]) > 0 {
+			quarters = append(quarters, quarterName(q))
+			if alternating(sortedYears(history[q]), observedYears(first, last, q)) {
+				alternate = true
+			}
+		}
+	}
+	pattern := ""
+	switch len(quarters) {
+	case 0:
+		return "no history"
+	case 1:
+		pattern = quarters[0] + " only"
+	case 3:
+		pattern = "every quarter"
+	default:
+		pattern = strings.Join(quarters, " and ")
+	}
+	if alternate {
+		pattern += ", alternate years"
+	}
+	return pattern
+}
+
+// PredictOffering estimates the probability that the course is offered in the
+// given term from its offering history over the loaded terms. Only the years
+// in which that quarter was loaded count, recent years more than older ones,
+// and alternate-year patterns are projected forward. Confidence grows with
+// the number of years observed and with how consistent they are. Courses
+// without any history are Unknown.
+func (course Course) PredictOffering(term string, terms []string) Forecast {
+	forecast := Forecast{Term: term, Probability: 0.5, Confidence: 0.0, Pattern: course.OfferingPattern(terms)}
+	history := course.OfferingHistory()
+	first, last, ok := historyWindow(history, terms)
+	if !ok || !IsAcademicTerm(term) {
+		forecast.Unknown = true
+		return forecast
+	}
+	target, _ := strconv.Atoi(term[0:4])
+	years := sortedYears(history[Quarter(term)])
+	observed := observedYears(first, last, Quarter(term))
+	n := float64(len(observed))
+	
+	if alternating(years, observed) {
+		if (target - years[len(years)-1]) % 2 == 0 {
+			forecast.Probability = 0.9
+		} else {
+			forecast.Probability = 0.1
+		}
+		forecast.Confidence = n / (n + 2.0)
+		return forecast
+	}
+	
+	offered := make(map[int]bool, 0)
+	for _, y := range years {
+		offered[y] = true
+	}
+	weighted, total := 0.0, 0.0
+	for _, y := range observed {
+		w := math.Pow(recencyDecay, float64(observed[len(observed)-1] - y))
+		total += w
+		if offered[y] {
+			weighted += w
+		}
+	}
+	// Laplace smoothing keeps a single year of history from being conclusive.
+	forecast.Probability = (weighted + 0.5) / (total + 1.0)
+	consistency := math.Abs(2.0*forecast.Probability - 1.0)
+	forecast.Confidence = (n / (n + 2.0)) * consistency
+	return forecast
+}
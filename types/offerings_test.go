@@ -0,0 +1,103 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package types
+
+import (
+	"testing"
+)
+
+func TestPredictOffering(t *testing.T) {
+	loaded := []string{"2012-92", "2013-03", "2018-14"}
+	tests := []struct {
+		name    string
+		offered map[string][]int
+		terms   []string
+		term    string
+		likely  bool
+	}{
+		{"one academic year, next Fall", map[string][]int{"F": {2017}, "W": {2018}, "S": {2018}}, nil, "2018-92", true},
+		{"every quarter for two years, next Fall", map[string][]int{"F": {2016, 2017}, "W": {2017, 2018}, "S": {2017, 2018}}, nil, "2018-92", true},
+		{"every quarter for two years, next Winter", map[string][]int{"F": {2016, 2017}, "W": {2017, 2018}, "S": {2017, 2018}}, nil, "2019-03", true},
+		{"Fall only, next Winter", map[string][]int{"F": {2014, 2015, 2016, 2017}}, nil, "2019-03", false},
+		{"Fall only, next Fall", map[string][]int{"F": {2014, 2015, 2016, 2017}}, nil, "2018-92", true},
+		{"dropped from Spring", map[string][]int{"F": {2014, 2015, 2016, 2017}, "S": {2015}}, nil, "2019-14", false},
+		{"alternate years, on", map[string][]int{"W": {2013, 2015, 2017}, "F": {2012, 2013, 2014, 2015, 2016, 2017}}, nil, "2019-03", true},
+		{"alternate years, off", map[string][]int{"W": {2013, 2015, 2017}, "F": {2012, 2013, 2014, 2015, 2016, 2017}}, nil, "2018-03", false},
+		// On its own, a single offering looks like a pattern. Over the loaded
+		// terms, it's a course that hasn't been offered since.
+		{"once, on its own", map[string][]int{"F": {2013}}, nil, "2018-92", true},
+		{"once, within the loaded terms", map[string][]int{"F": {2013}}, loaded, "2018-92", false},
+		{"every other Winter within the loaded terms", map[string][]int{"W": {2013, 2015, 2017}}, loaded, "2019-03", true},
+		{"every other Winter within the loaded terms, off", map[string][]int{"W": {2013, 2015, 2017}}, loaded, "2018-03", false},
+	}
+	for _, test := range tests {
+		course := Course{Offered: test.offered}
+		forecast := course.PredictOffering(test.term, test.terms)
+		if forecast.Unknown {
+			t.Errorf("%v: PredictOffering(%v) is unknown", test.name, test.term)
+		}
+		if forecast.Likely() != test.likely {
+			t.Errorf("%v: PredictOffering(%v) = %.3f, want likely %v", test.name, test.term, forecast.Probability, test.likely)
+		}
+	}
+}
+
+func TestPredictOfferingWithoutHistory(t *testing.T) {
+	for _, terms := range [][]string{nil, {"2017-92", "2018-14"}} {
+		forecast := Course{}.PredictOffering("2018-92", terms)
+		if !forecast.Unknown || forecast.Likely() {
+			t.Errorf("PredictOffering over %v without history = %+v, want unknown", terms, forecast)
+		}
+		if forecast.Confidence != 0.0 {
+			t.Errorf("PredictOffering over %v without history has confidence %.3f, want 0", terms, forecast.Confidence)
+		}
+		if pattern := (Course{}).OfferingPattern(terms); pattern != "no history" {
+			t.Errorf("OfferingPattern over %v without history = %v, want no history", terms, pattern)
+		}
+	}
+}
+
+func TestObservedYears(t *testing.T) {
+	tests := []struct {
+		first    string
+		last     string
+		quarter  string
+		expected []int
+	}{
+		{"2017-92", "2018-14", "F", []int{2017}},
+		{"2017-92", "2018-14", "W", []int{2018}},
+		{"2017-03", "2018-03", "S", []int{2017}},
+		{"2016-92", "2018-14", "F", []int{2016, 2017}},
+	}
+	for _, test := range tests {
+		years := observedYears(test.first, test.last, test.quarter)
+		if len(years) != len(test.expected) {
+			t.Errorf("observedYears(%v, %v, %v) = %v, want %v", test.first, test.last, test.quarter, years, test.expected)
+			continue
+		}
+		for i := range years {
+			if years[i] != test.expected[i] {
+				t.Errorf("observedYears(%v, %v, %v) = %v, want %v", test.first, test.last, test.quarter, years, test.expected)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/* DegreeWorks Course Patterns */
+
+// CoursePattern is a course option as written in a DegreeWorks audit. Either
+// part may contain the `@` wildcard (`COMPSCI 1@`, `@ @`), and the number may
+// be an inclusive range of course levels (`100:199`).
+type CoursePattern struct {
+	Department string `json:"department"`
+	Number     string `json:"number"`
+}
+
+func NewCoursePattern(disc, num string) CoursePattern {
+	return CoursePattern{Department: strings.ToUpper(strings.TrimSpace(disc)), Number: strings.ToUpper(strings.TrimSpace(num))}
+}
+
+func (pattern CoursePattern) String() string {
+	return pattern.Department + " " + pattern.Number
+}
+
+func (pattern CoursePattern) IsLiteral() bool {
+	return !strings.Contains(pattern.String(), "@") && !strings.Contains(pattern.Number, ":")
+}
+
+func (pattern CoursePattern) Key() string {
+	return strings.Replace(pattern.Department + pattern.Number, " ", "", -1)
+}
+
+func (pattern CoursePattern) Matches(course Course) bool {
+	department := strings.Replace(strings.ToUpper(course.Department), " ", "", -1)
+	if !matchWildcard(strings.Replace(pattern.Department, " ", "", -1), department) {
+		return false
+	}
+	number := strings.ToUpper(strings.TrimSpace(course.Number))
+	if bounds := strings.SplitN(pattern.Number, ":", 2); len(bounds) == 2 {
+		low, err1 := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		high, err2 := strconv.Atoi(strings.TrimSpace(bounds[1]))
+		level := course.Level()
+		return (err1 == nil) && (err2 == nil) && (len(number) > 0) && (low <= level) && (level <= high)
+	}
+	return matchWildcard(pattern.Number, number)
+}
+
+// matchWildcard matches s against a pattern in which `@` stands for any run
+// of characters, including none. Everything else, `/` and `&` included, is
+// matched literally.
+func matchWildcard(pattern, s string) bool {
+	parts := strings.Split(pattern, "@")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	matched, err := regexp.MatchString("^" + strings.Join(parts, ".*") + "$", s)
+	return (err == nil) && matched
+}
+
+// Expand lists the keys of the catalogue courses matching the pattern, minus
+// any matching one of the exceptions.
+func (catalogue Catalogue) Expand(pattern CoursePattern, except []CoursePattern) []string {
+	keys := make([]string, 0)
+	for key, course := range catalogue.Courses {
+		if !pattern.Matches(course) {
+			continue
+		}
+		excluded := false
+		for _, e := range except {
+			if e.Matches(course) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
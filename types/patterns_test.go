@@ -0,0 +1,104 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoursePatternMatches(t *testing.T) {
+	tests := []struct {
+		department string
+		number     string
+		course     Course
+		expected   bool
+	}{
+		{"COMPSCI", "161", Course{Department: "COMPSCI", Number: "161"}, true},
+		{"COMPSCI", "1@", Course{Department: "COMPSCI", Number: "161"}, true},
+		{"COMPSCI", "1@", Course{Department: "COMPSCI", Number: "61"}, false},
+		{"COMPSCI", "@", Course{Department: "COMPSCI", Number: "H198"}, true},
+		{"@", "@", Course{Department: "MATH", Number: "2A"}, true},
+		{"I&C SCI", "3@", Course{Department: "I&C SCI", Number: "32A"}, true},
+		{"CRM/LAW", "C1@", Course{Department: "CRM/LAW", Number: "C102"}, true},
+		{"CRM/@", "@", Course{Department: "CRM/LAW", Number: "C7"}, true},
+		{"@", "1@", Course{Department: "CRM/LAW", Number: "C102"}, false},
+		{"COMPSCI", "1?1", Course{Department: "COMPSCI", Number: "161"}, false},
+		{"COMPSCI", "1[0-9]1", Course{Department: "COMPSCI", Number: "161"}, false},
+		{"COMPSCI", "100:199", Course{Department: "COMPSCI", Number: "H195"}, true},
+		{"COMPSCI", "100:199", Course{Department: "COMPSCI", Number: "299"}, false},
+		{"COMPSCI", "100:199", Course{Department: "COMPSCI", Number: ""}, false},
+	}
+	for _, test := range tests {
+		pattern := NewCoursePattern(test.department, test.number)
+		if matches := pattern.Matches(test.course); matches != test.expected {
+			t.Errorf("%v matches %v %v = %v, want %v", pattern, test.course.Department, test.course.Number, matches, test.expected)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	catalogue := Catalogue{Courses: map[string]Course{
+		"COMPSCI161":  {Department: "COMPSCI", Number: "161"},
+		"COMPSCI171":  {Department: "COMPSCI", Number: "171"},
+		"COMPSCI61":   {Department: "COMPSCI", Number: "61"},
+		"CRM/LAWC102": {Department: "CRM/LAW", Number: "C102"},
+	}}
+	tests := []struct {
+		pattern  CoursePattern
+		except   []CoursePattern
+		expected []string
+	}{
+		{NewCoursePattern("COMPSCI", "1@"), []CoursePattern{}, []string{"COMPSCI161", "COMPSCI171"}},
+		{NewCoursePattern("COMPSCI", "1@"), []CoursePattern{NewCoursePattern("COMPSCI", "161")}, []string{"COMPSCI171"}},
+		{NewCoursePattern("CRM/LAW", "@"), []CoursePattern{}, []string{"CRM/LAWC102"}},
+		{NewCoursePattern("@", "@"), []CoursePattern{NewCoursePattern("COMPSCI", "@")}, []string{"CRM/LAWC102"}},
+	}
+	for _, test := range tests {
+		if keys := catalogue.Expand(test.pattern, test.except); !reflect.DeepEqual(keys, test.expected) {
+			t.Errorf("Expand(%v, %v) = %v, want %v", test.pattern, test.except, keys, test.expected)
+		}
+	}
+}
+
+func TestRequirementAccepts(t *testing.T) {
+	req := Requirement{
+		Options:  []string{"COMPSCI161", "COMPSCI199"},
+		Patterns: []CoursePattern{NewCoursePattern("COMPSCI", "1@")},
+		Except:   []CoursePattern{NewCoursePattern("COMPSCI", "199"), NewCoursePattern("COMPSCI", "H1@")},
+	}
+	tests := []struct {
+		course   Course
+		expected bool
+	}{
+		{Course{Department: "COMPSCI", Number: "161"}, true},
+		{Course{Department: "COMPSCI", Number: "171"}, true},
+		{Course{Department: "COMPSCI", Number: "199"}, false},
+		{Course{Department: "COMPSCI", Number: "H198"}, false},
+		{Course{Department: "COMPSCI", Number: "61"}, false},
+		{Course{Department: "MATH", Number: "121A"}, false},
+	}
+	for _, test := range tests {
+		if accepts := req.Accepts(test.course); accepts != test.expected {
+			t.Errorf("Accepts(%v) = %v, want %v", test.course.Key(), accepts, test.expected)
+		}
+	}
+}
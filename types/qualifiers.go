@@ -0,0 +1,208 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+/* DegreeWorks Qualifiers */
+
+// Qualifiers restrict which completed classes count toward a rule or block.
+// Limits that are absent from the audit are nil, so that a limit of zero
+// (e.g. no pass/no pass classes at all) can still be expressed.
+type Qualifiers struct {
+	MinGrade     string   `json:"minGrade,omitempty"`
+	MinGPA       float64  `json:"minGPA,omitempty"`
+	MaxPassfail  *int     `json:"maxPassfail,omitempty"`
+	MaxClasses   *int     `json:"maxClasses,omitempty"`
+	MaxCredits   *float64 `json:"maxCredits,omitempty"`
+	NonExclusive bool     `json:"nonExclusive,omitempty"`
+	ShareWith    []string `json:"shareWith,omitempty"`
+}
+
+func IsPassfail(grade string) bool {
+	switch strings.ToUpper(strings.TrimSpace(grade)) {
+	case "P", "NP", "S", "U":
+		return true
+	}
+	return false
+}
+
+// Admits reports whether a single completed course meets the grade
+// qualifier, and why not when it doesn't.
+func (qualifiers Qualifiers) Admits(course Course) (bool, string) {
+	grade := strings.TrimSpace(course.Grade)
+	if (len(qualifiers.MinGrade) == 0) || (len(grade) == 0) || (grade == "IP") {
+		return true, ""
+	}
+	if IsPassfail(grade) {
+		return false, fmt.Sprintf("%v doesn't count: pass/no pass grade %v doesn't meet the minimum %v", course.Key(), grade, qualifiers.MinGrade)
+	} else if cmpGrade(grade, qualifiers.MinGrade) > 0 {
+		return false, fmt.Sprintf("%v doesn't count: grade %v is below the minimum %v", course.Key(), grade, qualifiers.MinGrade)
+	}
+	return true, ""
+}
+
+// Apply narrows the requirements' completed classes to the ones that count
+// under the qualifiers, in the order the audit applied them. The limits hold
+// across all of the requirements together, and the classes beyond them are
+// dropped rather than failing the requirements.
+func (qualifiers Qualifiers) Apply(student *Student, reqs []Requirement) ([]Requirement, []string) {
+	t := qualifiers.tally()
+	counted, messages := qualifiers.apply(student, reqs, []*tally{t})
+	return counted, append(messages, t.messages...)
+}
+
+// apply drops the classes that don't meet the grade qualifier, along with
+// those that don't fit within every one of the tallies.
+func (qualifiers Qualifiers) apply(student *Student, reqs []Requirement, tallies []*tally) ([]Requirement, []string) {
+	messages := make([]string, 0)
+	result := make([]Requirement, 0, len(reqs))
+	for _, req := range reqs {
+		counted := make([]string, 0)
+		removed := 0.0
+		for _, key := range req.Completed {
+			course := student.Courses[key]
+			if ok, message := qualifiers.Admits(course); !ok {
+				messages = append(messages, message)
+				removed += course.Units
+				continue
+			}
+			if !fits(tallies, key, course) {
+				removed += course.Units
+				continue
+			}
+			counted = append(counted, key)
+		}
+		req.Completed = counted
+		if req.CreditsApplied -= removed; req.CreditsApplied < 0.0 {
+			req.CreditsApplied = 0.0
+		}
+		result = append(result, req)
+	}
+	return result, messages
+}
+
+// tally keeps count of the classes counted toward a rule or block, against
+// its pass/no pass, class and unit limits. A class is only counted once, no
+// matter how many of the requirements beneath it list it.
+type tally struct {
+	qualifiers Qualifiers
+	counted    map[string]bool
+	passfail   int
+	classes    int
+	units      float64
+	messages   []string
+}
+
+func (qualifiers Qualifiers) tally() *tally {
+	return &tally{qualifiers: qualifiers, counted: make(map[string]bool, 0), messages: make([]string, 0)}
+}
+
+// fits reports whether the class can still be counted, noting why not when
+// it can't.
+func (t *tally) fits(key string, course Course) bool {
+	if t.counted[key] {
+		return true
+	}
+	message := ""
+	if (t.qualifiers.MaxPassfail != nil) && IsPassfail(course.Grade) && (t.passfail >= *t.qualifiers.MaxPassfail) {
+		message = fmt.Sprintf("%v doesn't count: only %d pass/no pass class(es) allowed", key, *t.qualifiers.MaxPassfail)
+	} else if (t.qualifiers.MaxClasses != nil) && (t.classes >= *t.qualifiers.MaxClasses) {
+		message = fmt.Sprintf("%v doesn't count: only %d class(es) allowed", key, *t.qualifiers.MaxClasses)
+	} else if (t.qualifiers.MaxCredits != nil) && (t.units + course.Units > *t.qualifiers.MaxCredits) {
+		message = fmt.Sprintf("%v doesn't count: only %v unit(s) allowed", key, *t.qualifiers.MaxCredits)
+	}
+	if len(message) > 0 {
+		t.messages = append(t.messages, message)
+		return false
+	}
+	return true
+}
+
+func (t *tally) count(key string, course Course) {
+	if t.counted[key] {
+		return
+	}
+	t.counted[key] = true
+	t.classes++
+	t.units += course.Units
+	if IsPassfail(course.Grade) {
+		t.passfail++
+	}
+}
+
+// fits counts the class toward all of the tallies if it fits within each.
+func fits(tallies []*tally, key string, course Course) bool {
+	for _, t := range tallies {
+		if !t.fits(key, course) {
+			return false
+		}
+	}
+	for _, t := range tallies {
+		t.count(key, course)
+	}
+	return true
+}
+
+// CheckGPA compares the GPA of the counted classes against the minimum GPA
+// qualifier. Classes without letter grades are left out.
+func (qualifiers Qualifiers) CheckGPA(student *Student, keys []string) (bool, string) {
+	if qualifiers.MinGPA == 0.0 {
+		return true, ""
+	}
+	points, credits := 0.0, 0.0
+	for _, key := range keys {
+		course := student.Courses[key]
+		if p, ok := GradePoints(course.Grade); ok {
+			units := course.Units
+			if units == 0.0 {
+				units = 1.0
+			}
+			points += p * units
+			credits += units
+		}
+	}
+	if (credits == 0.0) || (points / credits >= qualifiers.MinGPA) {
+		return true, ""
+	}
+	return false, fmt.Sprintf("GPA %.2f is below the minimum %.2f", points / credits, qualifiers.MinGPA)
+}
+
+// SharesWith reports whether classes may count toward both blocks.
+func (block Block) SharesWith(other Block) bool {
+	if block.Qualifiers.NonExclusive || other.Qualifiers.NonExclusive {
+		return true
+	}
+	for _, a := range []Block{block, other} {
+		b := other
+		if a.Title == other.Title {
+			b = block
+		}
+		for _, share := range a.Qualifiers.ShareWith {
+			if strings.EqualFold(share, b.ReqType) || strings.EqualFold(share, b.ReqValue) || strings.EqualFold(share, b.Title) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func qualifierStudent() *Student {
+	return &Student{Courses: map[string]Course{
+		"COMPSCI161": {Department: "COMPSCI", Number: "161", Units: 4.0, Grade: "A"},
+		"COMPSCI171": {Department: "COMPSCI", Number: "171", Units: 4.0, Grade: "C-"},
+		"COMPSCI178": {Department: "COMPSCI", Number: "178", Units: 4.0, Grade: "P"},
+		"COMPSCI199": {Department: "COMPSCI", Number: "199", Units: 4.0, Grade: "P"},
+		"COMPSCI122": {Department: "COMPSCI", Number: "122", Units: 4.0, Grade: "IP"},
+	}, Taken: map[string]bool{"COMPSCI161": true, "COMPSCI171": true, "COMPSCI178": true, "COMPSCI199": true, "COMPSCI122": true}}
+}
+
+func TestQualifiersApply(t *testing.T) {
+	one, two := 1, 2
+	eight := 8.0
+	tests := []struct {
+		name       string
+		qualifiers Qualifiers
+		completed  []string
+		counted    []string
+		messages   int
+	}{
+		{"no qualifiers", Qualifiers{}, []string{"COMPSCI161", "COMPSCI171"}, []string{"COMPSCI161", "COMPSCI171"}, 0},
+		{"minimum grade", Qualifiers{MinGrade: "C"}, []string{"COMPSCI161", "COMPSCI171"}, []string{"COMPSCI161"}, 1},
+		{"minimum grade rejects pass/no pass", Qualifiers{MinGrade: "C"}, []string{"COMPSCI178"}, []string{}, 1},
+		{"minimum grade skips in progress", Qualifiers{MinGrade: "C"}, []string{"COMPSCI122"}, []string{"COMPSCI122"}, 0},
+		{"pass/no pass limit", Qualifiers{MaxPassfail: &one}, []string{"COMPSCI178", "COMPSCI199"}, []string{"COMPSCI178"}, 1},
+		{"no pass/no pass at all", Qualifiers{MaxPassfail: new(int)}, []string{"COMPSCI161", "COMPSCI178"}, []string{"COMPSCI161"}, 1},
+		{"class limit", Qualifiers{MaxClasses: &two}, []string{"COMPSCI161", "COMPSCI171", "COMPSCI178"}, []string{"COMPSCI161", "COMPSCI171"}, 1},
+		{"unit limit", Qualifiers{MaxCredits: &eight}, []string{"COMPSCI161", "COMPSCI171", "COMPSCI178"}, []string{"COMPSCI161", "COMPSCI171"}, 1},
+	}
+	for _, test := range tests {
+		req := Requirement{Required: 1, Completed: test.completed, CreditsApplied: 4.0 * float64(len(test.completed))}
+		requirements, messages := test.qualifiers.Apply(qualifierStudent(), []Requirement{req})
+		counted := requirements[0]
+		if !reflect.DeepEqual(counted.Completed, test.counted) {
+			t.Errorf("%v: counted %v, want %v", test.name, counted.Completed, test.counted)
+		}
+		if counted.CreditsApplied != 4.0 * float64(len(test.counted)) {
+			t.Errorf("%v: CreditsApplied = %v, want %v", test.name, counted.CreditsApplied, 4.0 * float64(len(test.counted)))
+		}
+		if len(messages) != test.messages {
+			t.Errorf("%v: messages = %v, want %d", test.name, messages, test.messages)
+		}
+	}
+}
+
+func TestQualifiersApplyAcrossRequirements(t *testing.T) {
+	one, two := 1, 2
+	tests := []struct {
+		name       string
+		qualifiers Qualifiers
+		counted    [][]string
+		messages   int
+	}{
+		{"no limits", Qualifiers{}, [][]string{{"COMPSCI161", "COMPSCI178"}, {"COMPSCI171", "COMPSCI199"}}, 0},
+		{"class limit", Qualifiers{MaxClasses: &two}, [][]string{{"COMPSCI161", "COMPSCI178"}, {}}, 2},
+		{"pass/no pass limit", Qualifiers{MaxPassfail: &one}, [][]string{{"COMPSCI161", "COMPSCI178"}, {"COMPSCI171"}}, 1},
+	}
+	for _, test := range tests {
+		reqs := []Requirement{
+			{Required: 2, Completed: []string{"COMPSCI161", "COMPSCI178"}},
+			{Required: 2, Completed: []string{"COMPSCI171", "COMPSCI199"}},
+		}
+		requirements, messages := test.qualifiers.Apply(qualifierStudent(), reqs)
+		for i, req := range requirements {
+			if !reflect.DeepEqual(req.Completed, test.counted[i]) {
+				t.Errorf("%v: requirement %d counted %v, want %v", test.name, i, req.Completed, test.counted[i])
+			}
+		}
+		if len(messages) != test.messages {
+			t.Errorf("%v: messages = %v, want %d", test.name, messages, test.messages)
+		}
+	}
+}
+
+func TestQualifiersCheckGPA(t *testing.T) {
+	tests := []struct {
+		name       string
+		qualifiers Qualifiers
+		keys       []string
+		ok         bool
+	}{
+		{"no minimum", Qualifiers{}, []string{"COMPSCI161", "COMPSCI171"}, true},
+		{"GPA met", Qualifiers{MinGPA: 2.5}, []string{"COMPSCI161", "COMPSCI171"}, true},
+		{"GPA not met", Qualifiers{MinGPA: 3.0}, []string{"COMPSCI161", "COMPSCI171"}, false},
+		{"pass/no pass left out", Qualifiers{MinGPA: 3.0}, []string{"COMPSCI161", "COMPSCI178"}, true},
+	}
+	for _, test := range tests {
+		if ok, _ := test.qualifiers.CheckGPA(qualifierStudent(), test.keys); ok != test.ok {
+			t.Errorf("%v: CheckGPA = %v, want %v", test.name, ok, test.ok)
+		}
+	}
+}
+
+func TestRuleLimits(t *testing.T) {
+	two := 2
+	eight := 8.0
+	tests := []struct {
+		name       string
+		qualifiers Qualifiers
+		required   int
+		completed  bool
+		messages   int
+	}{
+		{"no limits", Qualifiers{}, 1, true, 0},
+		// The excess class is dropped, and what's left still completes one of
+		// the requirements.
+		{"class limit drops the excess", Qualifiers{MaxClasses: &two}, 1, true, 1},
+		{"unit limit drops the excess", Qualifiers{MaxCredits: &eight}, 1, true, 1},
+		{"class limit falls short", Qualifiers{MaxClasses: &two}, 2, false, 1},
+	}
+	for _, test := range tests {
+		rule := Rule{Label: "Electives", Kind: CourseRule, Required: test.required, Qualifiers: test.qualifiers, Requirements: []Requirement{
+			{Required: 2, Options: []string{"COMPSCI161", "COMPSCI171"}, Completed: []string{"COMPSCI161", "COMPSCI171"}},
+			{Required: 1, Options: []string{"COMPSCI178"}, Completed: []string{"COMPSCI178"}},
+		}}
+		student := qualifierStudent()
+		if completed := rule.IsCompleted(student); completed != test.completed {
+			t.Errorf("%v: IsCompleted = %v, want %v", test.name, completed, test.completed)
+		}
+		if _, messages := rule.Check(student); len(messages) != test.messages {
+			t.Errorf("%v: messages = %v, want %d", test.name, messages, test.messages)
+		}
+		flattened := rule.Flatten(student)
+		if completed := (len(flattened) == 1) && flattened[0].IsCompleted(student); completed != test.completed {
+			t.Errorf("%v: Flatten = %v, completed %v, want %v", test.name, flattened, completed, test.completed)
+		}
+	}
+}
+
+func TestBlockLimits(t *testing.T) {
+	one, two := 1, 2
+	student := qualifierStudent()
+	rules := []Rule{
+		{Label: "A", Kind: CourseRule, Required: 1, Requirements: []Requirement{{Required: 1, Options: []string{"COMPSCI161"}, Completed: []string{"COMPSCI161"}}}},
+		{Label: "B", Kind: CourseRule, Required: 1, Requirements: []Requirement{{Required: 1, Options: []string{"COMPSCI171"}, Completed: []string{"COMPSCI171"}}}},
+	}
+	tests := []struct {
+		name       string
+		qualifiers Qualifiers
+		minGrade   string
+		completed  bool
+	}{
+		{"no limits", Qualifiers{}, "", true},
+		{"class limit", Qualifiers{MaxClasses: &one}, "", false},
+		{"class limit met", Qualifiers{MaxClasses: &two}, "", true},
+		{"minimum grade on a rule", Qualifiers{}, "C", false},
+	}
+	for _, test := range tests {
+		block := Block{Title: "Major", Rules: make([]Rule, len(rules)), Qualifiers: test.qualifiers}
+		copy(block.Rules, rules)
+		block.Rules[1].Qualifiers.MinGrade = test.minGrade
+		student.Blocks = []Block{block}
+		if completed := block.IsCompleted(student); completed != test.completed {
+			t.Errorf("%v: IsCompleted = %v, want %v", test.name, completed, test.completed)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+/* Course Recommendations */
+
+type Recommendation struct {
+	Course    string   `json:"course"`
+	Score     float64  `json:"score"`
+	Satisfies []string `json:"satisfies"`
+	Unlocks   int      `json:"unlocks"`
+	Offered   bool     `json:"offered"`
+	Rarity    float64  `json:"rarity"`
+}
+
+type Shortlist struct {
+	Term            string           `json:"term"`
+	Recommendations []Recommendation `json:"recommendations"`
+}
@@ -0,0 +1,62 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestShortlistJSON(t *testing.T) {
+	shortlist := Shortlist{Term: "2019-03", Recommendations: []Recommendation{
+		{Course: "COMPSCI161", Score: 6.5, Satisfies: []string{"Major: Electives"}, Unlocks: 2, Offered: true, Rarity: 0.5},
+	}}
+	tests := []struct {
+		name     string
+		student  Student
+		expected string
+		omitted  bool
+	}{
+		{"with recommendations", Student{Recommendations: []Shortlist{shortlist}},
+			`"recommendations":[{"term":"2019-03","recommendations":[{"course":"COMPSCI161","score":6.5,"satisfies":["Major: Electives"],"unlocks":2,"offered":true,"rarity":0.5}]}]`, false},
+		{"without recommendations", Student{}, `"recommendations"`, true},
+	}
+	for _, test := range tests {
+		data, err := json.Marshal(test.student)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if contains := strings.Contains(string(data), test.expected); contains == test.omitted {
+			t.Errorf("%v: %v in %s = %v, want %v", test.name, test.expected, data, contains, !test.omitted)
+		}
+		
+		var decoded Student
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if len(decoded.Recommendations) != len(test.student.Recommendations) {
+			t.Errorf("%v: decoded %d shortlists, want %d", test.name, len(decoded.Recommendations), len(test.student.Recommendations))
+		} else if (len(decoded.Recommendations) > 0) && (decoded.Recommendations[0].Recommendations[0].Unlocks != 2) {
+			t.Errorf("%v: decoded %+v, want %+v", test.name, decoded.Recommendations[0], shortlist)
+		}
+	}
+}
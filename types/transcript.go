@@ -0,0 +1,163 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/* Transcript */
+
+var gradePoints = map[string]float64{
+	"A+": 4.0, "A": 4.0, "A-": 3.7,
+	"B+": 3.3, "B": 3.0, "B-": 2.7,
+	"C+": 2.3, "C": 2.0, "C-": 1.7,
+	"D+": 1.3, "D": 1.0, "D-": 0.7,
+	"F": 0.0,
+}
+
+// GradePoints returns the grade points per unit for a letter grade, or false
+// for grades that don't count toward the GPA (e.g. P, NP, W, IP).
+func GradePoints(grade string) (float64, bool) {
+	points, ok := gradePoints[strings.ToUpper(strings.TrimSpace(grade))]
+	return points, ok
+}
+
+// TransferCode reads the `Transfer` attribute of a DegreeWorks class. `T`
+// (or `Y` in some audits) marks coursework transferred from another school,
+// and `E` (or `X`) marks credit by exam, such as AP or IB. Classes taken here
+// leave it empty or set it to `N`. Other codes aren't recognized.
+func TransferCode(code string) (transfer bool, testCredit bool, ok bool) {
+	switch strings.ToUpper(strings.TrimSpace(code)) {
+	case "", "N":
+		return false, false, true
+	case "T", "Y":
+		return true, false, true
+	case "E", "X":
+		return true, true, true
+	}
+	return false, false, false
+}
+
+// TranscriptEntry is one class enrollment. GradePoints is the total for the
+// class (points per unit times GPACredits), as DegreeWorks reports it in
+// `Gpa_grade_pts`.
+type TranscriptEntry struct {
+	Course      string  `json:"course"`
+	Department  string  `json:"department"`
+	Number      string  `json:"number"`
+	Title       string  `json:"title"`
+	Term        string  `json:"term"`
+	Grade       string  `json:"grade"`
+	Credits     float64 `json:"credits"`
+	InProgress  bool    `json:"inProgress"`
+	Transfer    bool    `json:"transfer"`
+	TestCredit  bool    `json:"testCredit"`
+	Repeat      bool    `json:"repeat"`
+	GPACredits  float64 `json:"gpaCredits"`
+	GradePoints float64 `json:"gradePoints"`
+}
+
+type Transcript []TranscriptEntry
+
+// termOrder splits a term code, e.g. `2018-14` or `201814`, into its year and
+// quarter code, whose numeric order is chronological.
+func termOrder(term string) (int, int, bool) {
+	digits := strings.Replace(term, "-", "", -1)
+	if len(digits) != 6 {
+		return 0, 0, false
+	}
+	year, errY := strconv.Atoi(digits[0:4])
+	code, errC := strconv.Atoi(digits[4:])
+	return year, code, (errY == nil) && (errC == nil)
+}
+
+// Terms lists the terms of the transcript in chronological order. Terms that
+// aren't term codes, such as those of transfer work, come first.
+func (transcript Transcript) Terms() []string {
+	terms := make([]string, 0)
+	seen := make(map[string]bool, 0)
+	for _, entry := range transcript {
+		if !seen[entry.Term] {
+			seen[entry.Term] = true
+			terms = append(terms, entry.Term)
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		yearI, codeI, okI := termOrder(terms[i])
+		yearJ, codeJ, okJ := termOrder(terms[j])
+		if okI != okJ {
+			return !okI
+		} else if !okI || ((yearI == yearJ) && (codeI == codeJ)) {
+			return terms[i] < terms[j]
+		} else if yearI != yearJ {
+			return yearI < yearJ
+		}
+		return codeI < codeJ
+	})
+	return terms
+}
+
+func (transcript Transcript) InTerm(term string) Transcript {
+	entries := make(Transcript, 0)
+	for _, entry := range transcript {
+		if entry.Term == term {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func (transcript Transcript) GPA() float64 {
+	points, credits := 0.0, 0.0
+	for _, entry := range transcript {
+		points += entry.GradePoints
+		credits += entry.GPACredits
+	}
+	if credits == 0.0 {
+		return 0.0
+	}
+	return points / credits
+}
+
+// Completed reports whether the course was completed with credit, excluding
+// in-progress enrollments.
+func (transcript Transcript) Completed(key string) bool {
+	for _, entry := range transcript {
+		if (entry.Course == key) && !entry.InProgress && (entry.Credits > 0.0) {
+			return true
+		}
+	}
+	return false
+}
+
+// Credits returns the most credits earned for the course in any completed
+// enrollment, so repeats aren't counted twice.
+func (transcript Transcript) Credits(key string) float64 {
+	credits := 0.0
+	for _, entry := range transcript {
+		if (entry.Course == key) && !entry.InProgress && (entry.Credits > credits) {
+			credits = entry.Credits
+		}
+	}
+	return credits
+}
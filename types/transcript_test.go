@@ -0,0 +1,95 @@
+//
+//  peterplanner
+//  Copyright (c) 2017 Nicolas Gomollon <nicolas@gomollon.me>
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTranscriptTerms(t *testing.T) {
+	tests := []struct {
+		name     string
+		terms    []string
+		expected []string
+	}{
+		{"term codes", []string{"2018-03", "2017-92", "2018-14", "2017-92"}, []string{"2017-92", "2018-03", "2018-14"}},
+		{"summer sessions", []string{"2018-92", "2018-76", "2018-25", "2018-14"}, []string{"2018-14", "2018-25", "2018-76", "2018-92"}},
+		{"mixed formats", []string{"2018-03", "201792", "2017-14"}, []string{"2017-14", "201792", "2018-03"}},
+		{"transfer work first", []string{"2017-92", "TRANSFER", ""}, []string{"", "TRANSFER", "2017-92"}},
+	}
+	for _, test := range tests {
+		transcript := make(Transcript, 0)
+		for _, term := range test.terms {
+			transcript = append(transcript, TranscriptEntry{Course: "COMPSCI161", Term: term})
+		}
+		if terms := transcript.Terms(); !reflect.DeepEqual(terms, test.expected) {
+			t.Errorf("%v: Terms = %v, want %v", test.name, terms, test.expected)
+		}
+	}
+}
+
+func TestTransferCode(t *testing.T) {
+	tests := []struct {
+		code       string
+		transfer   bool
+		testCredit bool
+		ok         bool
+	}{
+		{"", false, false, true},
+		{"N", false, false, true},
+		{"t", true, false, true},
+		{"Y", true, false, true},
+		{"E", true, true, true},
+		{" X ", true, true, true},
+		{"Q", false, false, false},
+	}
+	for _, test := range tests {
+		transfer, testCredit, ok := TransferCode(test.code)
+		if (transfer != test.transfer) || (testCredit != test.testCredit) || (ok != test.ok) {
+			t.Errorf("TransferCode(%q) = %v, %v, %v, want %v, %v, %v", test.code, transfer, testCredit, ok, test.transfer, test.testCredit, test.ok)
+		}
+	}
+}
+
+func TestTranscriptCredits(t *testing.T) {
+	transcript := Transcript{
+		{Course: "COMPSCI161", Term: "2017-92", Grade: "F", Credits: 0.0},
+		{Course: "COMPSCI161", Term: "2018-03", Grade: "B", Credits: 4.0, Repeat: true},
+		{Course: "COMPSCI171", Term: "2018-14", Grade: "IP", Credits: 4.0, InProgress: true},
+	}
+	tests := []struct {
+		key       string
+		completed bool
+		credits   float64
+	}{
+		{"COMPSCI161", true, 4.0},
+		{"COMPSCI171", false, 0.0},
+		{"COMPSCI178", false, 0.0},
+	}
+	for _, test := range tests {
+		if completed := transcript.Completed(test.key); completed != test.completed {
+			t.Errorf("Completed(%v) = %v, want %v", test.key, completed, test.completed)
+		}
+		if credits := transcript.Credits(test.key); credits != test.credits {
+			t.Errorf("Credits(%v) = %v, want %v", test.key, credits, test.credits)
+		}
+	}
+}
@@ -116,14 +116,57 @@ func SpringQuarter(year int) string {
 	return fmt.Sprintf("%v-14", year)
 }
 
+func Quarter(term string) string {
+	switch {
+	case IsFQ(term):
+		return "F"
+	case IsWQ(term):
+		return "W"
+	case IsSQ(term):
+		return "S"
+	}
+	return "--"
+}
+
+func NextTerm(term string) string {
+	year, _ := strconv.Atoi(term[0:4])
+	switch {
+	case IsFQ(term):
+		return WinterQuarter(year + 1)
+	case IsWQ(term):
+		return SpringQuarter(year)
+	}
+	return FallQuarter(year)
+}
+
+func TermName(term string) string {
+	switch {
+	case IsFQ(term):
+		return "Fall " + term[0:4]
+	case IsWQ(term):
+		return "Winter " + term[0:4]
+	case IsSQ(term):
+		return "Spring " + term[0:4]
+	}
+	return term
+}
+
 func YearFQ() int {
-	currentDate := time.Now()
+	return YearFQAsOf(DefaultClock.Now())
+}
+
+func YearFQAsOf(currentDate time.Time) int {
+	currentDate = currentDate.In(Location)
 	year := currentDate.Year()
 	return year
 }
 
 func YearWQ() int {
-	currentDate := time.Now()
+	return YearWQAsOf(DefaultClock.Now())
+}
+
+func YearWQAsOf(currentDate time.Time) int {
+	currentDate = currentDate.In(Location)
 	year := currentDate.Year()
 	month := currentDate.Month()
 	if month > time.June {
@@ -133,7 +176,11 @@ func YearWQ() int {
 }
 
 func YearSQ() int {
-	currentDate := time.Now()
+	return YearSQAsOf(DefaultClock.Now())
+}
+
+func YearSQAsOf(currentDate time.Time) int {
+	currentDate = currentDate.In(Location)
 	year := currentDate.Year()
 	month := currentDate.Month()
 	if month > time.June {
@@ -143,7 +190,11 @@ func YearSQ() int {
 }
 
 func AcademicYear() int {
-	currentDate := time.Now()
+	return AcademicYearAsOf(DefaultClock.Now())
+}
+
+func AcademicYearAsOf(currentDate time.Time) int {
+	currentDate = currentDate.In(Location)
 	year := currentDate.Year()
 	month := currentDate.Month()
 	if month <= time.June {
@@ -218,6 +269,12 @@ type Class struct {
 	Days       []time.Weekday `json:"days"`
 	Time       Time           `json:"time"`
 	Place      string         `json:"place"`
+	Final      string         `json:"final"`
+	Status     string         `json:"status"`
+}
+
+func (class Class) IsOpen() bool {
+	return class.Status == "OPEN"
 }
 
 type CourseGroup struct {
@@ -240,16 +297,19 @@ func (slice CourseGroups) Swap(i, j int) {
 }
 
 type Course struct {
-	Department    string             `json:"department"`
-	Number        string             `json:"number"`
-	Title         string             `json:"title"`
-	ShortTitle    string             `json:"stitle"`
-	Description   string             `json:"description"`
-	Grade         string             `json:"grade"`
-	Prerequisites [][]string         `json:"prerequisites"`
-	RequiredBy    CourseGroups       `json:"requiredby"`
-	Classes       map[string][]Class `json:"classes"`
-	Offered       map[string][]int   `json:"offered"`
+	Department    string                `json:"department"`
+	Number        string                `json:"number"`
+	Title         string                `json:"title"`
+	ShortTitle    string                `json:"stitle"`
+	Description   string                `json:"description"`
+	Grade         string                `json:"grade"`
+	Prerequisites [][]string            `json:"prerequisites"`
+	RequiredBy    CourseGroups          `json:"requiredby"`
+	Classes       map[string][]Class    `json:"classes"`
+	Bundles       map[string][][]string `json:"bundles"`
+	Offered       map[string][]int      `json:"offered"`
+	Units         float64               `json:"units"`
+	GE            []string              `json:"ge"`
 }
 
 func (course Course) Key() string {
@@ -259,15 +319,7 @@ func (course Course) Key() string {
 func (course Course) TermsOffered() map[string][]int {
 	termsOffered := make(map[string][]int, 0)
 	for k := range course.Classes {
-		t := "--"
-		switch {
-		case IsFQ(k):
-			t = "F"
-		case IsWQ(k):
-			t = "W"
-		case IsSQ(k):
-			t = "S"
-		}
+		t := Quarter(k)
 		y, _ := strconv.Atoi(k[0:4])
 		years := termsOffered[t]
 		if years == nil {
@@ -280,6 +332,99 @@ func (course Course) TermsOffered() map[string][]int {
 	return termsOffered
 }
 
+// OfferingHistory returns the years in which the course was offered, keyed by
+// quarter (`F`, `W`, `S`), preferring the precomputed `Offered` data.
+func (course Course) OfferingHistory() map[string][]int {
+	if len(course.Offered) > 0 {
+		return course.Offered
+	}
+	return course.TermsOffered()
+}
+
+// ClassBundles returns the combinations of classes that can be enrolled in
+// together. Courses without linked sections fall back to one class of each
+// section type.
+func (course Course) ClassBundles(yearTerm string) [][]Class {
+	classes := course.Classes[yearTerm]
+	byCode := make(map[string]Class, 0)
+	for _, class := range classes {
+		byCode[strings.TrimSpace(class.Code)] = class
+	}
+	bundles := make([][]Class, 0)
+	if codeBundles, ok := course.Bundles[yearTerm]; ok {
+		for _, codes := range codeBundles {
+			bundle := make([]Class, 0)
+			for _, code := range codes {
+				if class, ok := byCode[code]; ok {
+					bundle = append(bundle, class)
+				}
+			}
+			bundles = append(bundles, bundle)
+		}
+		return bundles
+	}
+	
+	kinds := make([]string, 0)
+	byType := make(map[string][]Class, 0)
+	for _, class := range classes {
+		t := strings.TrimSpace(class.Type)
+		if _, ok := byType[t]; !ok {
+			kinds = append(kinds, t)
+		}
+		byType[t] = append(byType[t], class)
+	}
+	if len(kinds) > 0 {
+		bundles = append(bundles, []Class{})
+	}
+	for _, t := range kinds {
+		expanded := make([][]Class, 0)
+		for _, bundle := range bundles {
+			for _, class := range byType[t] {
+				b := make([]Class, len(bundle), len(bundle)+1)
+				copy(b, bundle)
+				expanded = append(expanded, append(b, class))
+			}
+		}
+		bundles = expanded
+	}
+	return bundles
+}
+
+type Prerequisite struct {
+	Key     string
+	Grade   string
+	Negated bool
+	Coreq   bool
+}
+
+// ParsePrerequisite splits a prerequisite item, e.g. `I&C SCI 33|C`,
+// `MATH 2B||COREQ` or `NO COMPSCI 161`, into its parts.
+func ParsePrerequisite(item string) Prerequisite {
+	splitPrrq := strings.Split(item, "|")
+	prereq := Prerequisite{Key: strings.Replace(splitPrrq[0], " ", "", -1)}
+	if strings.HasPrefix(splitPrrq[0], "NO ") {
+		prereq.Key = strings.TrimPrefix(prereq.Key, "NO")
+		prereq.Negated = true
+	}
+	if len(splitPrrq) >= 2 {
+		prereq.Grade = splitPrrq[1]
+	}
+	prereq.Coreq = (len(splitPrrq) >= 3) && (splitPrrq[len(splitPrrq)-1] == "COREQ")
+	return prereq
+}
+
+// MetBy reports whether the student's completed courses meet the
+// prerequisite, including its minimum grade.
+func (prereq Prerequisite) MetBy(student *Student) bool {
+	if prereq.Negated {
+		return !student.Taken[prereq.Key]
+	} else if !student.Taken[prereq.Key] {
+		return false
+	}
+	grade := student.Courses[prereq.Key].Grade
+	return (len(grade) == 0) || (len(prereq.Grade) == 0) || (cmpGrade(grade, prereq.Grade) <= 0)
+}
+
 func (course Course) ClearedPrereqs(student *Student) bool {
 	for _, prereqsAND := range course.Prerequisites {
 		satisfied := false
@@ -291,7 +436,7 @@ func (course Course) ClearedPrereqs(student *Student) bool {
 				if !satisfied && strings.HasPrefix(splitPrrq[0], "NO ") {
 					prereq = strings.TrimPrefix(prereq, "NO")
 					satisfied = !(*student).Taken[prereq]
-				} else if satisfied && (len(splitPrrq) == 2) {
+				} else if satisfied && (len(splitPrrq) >= 2) {
 					c := (*student).Courses[prereq]
 					grade := splitPrrq[1]
 					if (len(c.Grade) != 0) && (len(grade) != 0) {
@@ -322,24 +467,212 @@ func (course Course) ClearedPrereqs(student *Student) bool {
 }
 
 type Requirement struct {
-	Required  int      `json:"required"`
-	Options   []string `json:"options"`
-	Completed []string `json:"completed"`
+	Required  int             `json:"required"`
+	Options   []string        `json:"options"`
+	Patterns  []CoursePattern `json:"patterns,omitempty"`
+	Except    []CoursePattern `json:"except,omitempty"`
+	Completed []string        `json:"completed"`
+	
+	// Credits is the number of units required, alongside (or instead of) a
+	// number of classes. Connector is `AND` when both thresholds must be met.
+	Credits        float64 `json:"credits"`
+	CreditsApplied float64 `json:"creditsApplied"`
+	Connector      string  `json:"connector,omitempty"`
 }
 
 func (req Requirement) IsCompleted() bool {
-	return len(req.Completed) >= req.Required
+	classes := len(req.Completed) >= req.Required
+	if req.Credits == 0.0 {
+		return classes
+	}
+	credits := req.CreditsApplied >= req.Credits
+	if req.Required == 0 {
+		return credits
+	} else if req.Connector == "AND" {
+		return classes && credits
+	}
+	return classes || credits
+}
+
+// Remaining estimates how many more classes the requirement needs, counting
+// unit thresholds in classes of the given size.
+func (req Requirement) Remaining(unitsPerClass float64) int {
+	if req.IsCompleted() {
+		return 0
+	}
+	classes := req.Required - len(req.Completed)
+	if (req.Credits == 0.0) || (unitsPerClass <= 0.0) {
+		return classes
+	}
+	credits := int(math.Ceil((req.Credits - req.CreditsApplied) / unitsPerClass))
+	if (req.Required == 0) || ((req.Connector == "AND") && (credits > classes)) || ((req.Connector != "AND") && (credits < classes)) {
+		return credits
+	}
+	return classes
+}
+
+// Progress describes how far along the requirement is, e.g. "8 of 16 units".
+func (req Requirement) Progress() string {
+	classes := fmt.Sprintf("%d of %d classes", len(req.Completed), req.Required)
+	if req.Credits == 0.0 {
+		return classes
+	}
+	credits := fmt.Sprintf("%v of %v units", req.CreditsApplied, req.Credits)
+	if req.Required == 0 {
+		return credits
+	} else if req.Connector == "AND" {
+		return classes + " and " + credits
+	}
+	return classes + " or " + credits
+}
+
+// Accepts reports whether the course counts toward the requirement, either
+// as a listed option or by matching one of its wildcard patterns. Excepted
+// courses never count.
+func (req Requirement) Accepts(course Course) bool {
+	for _, e := range req.Except {
+		if e.Matches(course) {
+			return false
+		}
+	}
+	key := course.Key()
+	for _, option := range req.Options {
+		if option == key {
+			return true
+		}
+	}
+	for _, pattern := range req.Patterns {
+		if pattern.Matches(course) {
+			return true
+		}
+	}
+	return false
+}
+
+// Status is DegreeWorks's own verdict on a rule or block, as opposed to the
+// one computed locally by IsCompleted.
+type Status struct {
+	Reported        bool    `json:"reported"`
+	PercentComplete float64 `json:"percentComplete"`
+	InProgress      bool    `json:"inProgress"`
 }
 
+func (status Status) IsSatisfied() bool {
+	return status.PercentComplete >= 100.0
+}
+
+// Rule kinds, as given by the `RuleType` attribute of DegreeWorks rules.
+const (
+	CourseRule    = "Course"
+	GroupRule     = "Group"
+	SubsetRule    = "Subset"
+	BlockRule     = "Block"
+	BlocktypeRule = "Blocktype"
+	NoncourseRule = "Noncourse"
+)
+
+// Rule is a node in a block's requirement tree. Course rules hold
+// requirements; Noncourse rules are only as complete as the audit reports;
+// Group rules need `Required` of their child rules and Subset rules need all
+// of them; Block and Blocktype rules are satisfied by another block of the
+// audit.
 type Rule struct {
+	ID           string        `json:"id,omitempty"`
 	Label        string        `json:"label"`
+	Kind         string        `json:"kind"`
 	Required     int           `json:"required"`
 	Requirements []Requirement `json:"requirements"`
+	Rules        []Rule        `json:"rules,omitempty"`
+	BlockType    string        `json:"blockType,omitempty"`
+	BlockValue   string        `json:"blockValue,omitempty"`
+	Qualifiers   Qualifiers    `json:"qualifiers"`
+	Status       Status        `json:"status"`
+	Exceptions   []Exception   `json:"exceptions,omitempty"`
+}
+
+// Check narrows the rule's requirements to the classes that count under its
+// qualifiers, with a message for every class that doesn't count and for a
+// GPA that falls short. Classes beyond the rule's limits are dropped, from
+// its child rules as well as its own requirements.
+func (rule Rule) Check(student *Student) ([]Requirement, []string) {
+	t := rule.Qualifiers.tally()
+	limited, messages := rule.limited(student, []*tally{t})
+	messages = append(messages, t.messages...)
+	if ok, message := rule.Qualifiers.CheckGPA(student, limited.completed()); !ok {
+		messages = append(messages, message)
+	}
+	return limited.Requirements, messages
+}
+
+// limited drops the classes that don't count from the rule and the rules
+// beneath it, counting the rest toward the tallies, which end with the rule's
+// own. The messages are the grade messages for the rule's own requirements.
+func (rule Rule) limited(student *Student, tallies []*tally) (Rule, []string) {
+	children := make([]Rule, len(rule.Rules))
+	for i, child := range rule.Rules {
+		children[i], _ = child.limited(student, append(tallies[:len(tallies):len(tallies)], child.Qualifiers.tally()))
+	}
+	rule.Rules = children
+	var messages []string
+	rule.Requirements, messages = rule.Qualifiers.apply(student, rule.Requirements, tallies)
+	return rule, messages
+}
+
+// completed lists the classes completed in the rule and the rules beneath it.
+func (rule Rule) completed() []string {
+	keys := make([]string, 0)
+	for _, child := range rule.Rules {
+		keys = append(keys, child.completed()...)
+	}
+	for _, req := range rule.Requirements {
+		keys = append(keys, req.Completed...)
+	}
+	return keys
+}
+
+// Counted lists the completed classes that count toward the rule, including
+// those counted by its child rules.
+func (rule Rule) Counted(student *Student) []string {
+	limited, _ := rule.limited(student, []*tally{rule.Qualifiers.tally()})
+	return limited.completed()
 }
 
 func (rule Rule) IsCompleted(student *Student) bool {
+	if rule.IsWaived() {
+		return true
+	}
+	rule, _ = rule.limited(student, []*tally{rule.Qualifiers.tally()})
+	if ok, _ := rule.Qualifiers.CheckGPA(student, rule.completed()); !ok {
+		return false
+	}
+	switch rule.Kind {
+	case BlockRule, BlocktypeRule:
+		for _, block := range student.Blocks {
+			if (block.ReqType == rule.BlockType) && ((len(rule.BlockValue) == 0) || (block.ReqValue == rule.BlockValue)) {
+				return block.IsCompleted(student)
+			}
+		}
+		return false
+	case NoncourseRule:
+		return rule.Status.Reported && rule.Status.IsSatisfied()
+	case GroupRule, SubsetRule:
+		if len(rule.Rules) > 0 {
+			required := rule.Required
+			if rule.Kind == SubsetRule {
+				required = len(rule.Rules)
+			}
+			completedCount := 0
+			for _, child := range rule.Rules {
+				if child.IsCompleted(student) {
+					completedCount++
+				}
+			}
+			return completedCount >= required
+		}
+	}
+	requirements, _ := rule.Check(student)
 	completedCount := 0
-	for _, req := range rule.Requirements {
+	for _, req := range requirements {
 		if req.IsCompleted() {
 			completedCount++
 			if completedCount >= rule.Required {
@@ -350,23 +683,159 @@ func (rule Rule) IsCompleted(student *Student) bool {
 	return false
 }
 
+func (rule Rule) holdsRequirements() bool {
+	return (len(rule.Rules) == 0) && (rule.Kind != BlockRule) && (rule.Kind != BlocktypeRule)
+}
+
+// Flatten collapses the rule into rules that directly hold requirements,
+// which is the shape the planners and the requirement matcher work with.
+// A group of course rules becomes one rule needing `Required` of their
+// requirements. Deeper groups keep their completed children plus the first
+// incomplete ones still needed, so the result never asks for more than the
+// tree does. Waived rules flatten to nothing, and so do block references, as
+// the referenced block is part of the audit in its own right; either way a
+// waived child counts toward its group's `Required`.
+func (rule Rule) Flatten(student *Student) []Rule {
+	if rule.IsWaived() {
+		return []Rule{}
+	}
+	rule, _ = rule.limited(student, []*tally{rule.Qualifiers.tally()})
+	if rule.holdsRequirements() {
+		rule.Requirements, _ = rule.Check(student)
+		return []Rule{rule}
+	}
+	if (rule.Kind == BlockRule) || (rule.Kind == BlocktypeRule) {
+		return []Rule{}
+	}
+	required := rule.Required
+	if rule.Kind == SubsetRule {
+		required = len(rule.Rules)
+	}
+	
+	shallow := true
+	for _, child := range rule.Rules {
+		if !child.holdsRequirements() || (len(child.Requirements) != 1) {
+			shallow = false
+			break
+		}
+	}
+	if shallow {
+		flattened := Rule{Label: rule.Label, Kind: rule.Kind, Required: required, Requirements: make([]Requirement, 0), Qualifiers: rule.Qualifiers}
+		for _, child := range rule.Rules {
+			counted, _ := child.Check(student)
+			if child.IsCompleted(student) && !counted[0].IsCompleted() {
+				// Waived children are satisfied without a completed class, so
+				// they're counted off rather than kept as needing one.
+				flattened.Required--
+				continue
+			}
+			flattened.Requirements = append(flattened.Requirements, counted[0])
+		}
+		if flattened.Required < 0 {
+			flattened.Required = 0
+		}
+		return []Rule{flattened}
+	}
+	
+	rules := make([]Rule, 0)
+	pending := make([]Rule, 0)
+	for _, child := range rule.Rules {
+		if child.IsCompleted(student) {
+			rules = append(rules, child.Flatten(student)...)
+			required--
+		} else {
+			pending = append(pending, child)
+		}
+	}
+	for i := 0; (i < required) && (i < len(pending)); i++ {
+		rules = append(rules, pending[i].Flatten(student)...)
+	}
+	return rules
+}
+
 type Block struct {
-	ReqType string `json:"type"`
-	Title   string `json:"title"`
-	Rules   []Rule `json:"rules"`
+	ReqType        string        `json:"type"`
+	ReqValue       string        `json:"value"`
+	Title          string        `json:"title"`
+	Rules          []Rule        `json:"rules"`
+	Conditionals   []Conditional `json:"conditionals"`
+	CreditsApplied float64       `json:"creditsApplied"`
+	Qualifiers     Qualifiers    `json:"qualifiers"`
+	Status         Status        `json:"status"`
+}
+
+func (block Block) IsCompleted(student *Student) bool {
+	limited, _ := block.limited(student)
+	for _, rule := range limited.Rules {
+		if !rule.IsCompleted(student) {
+			return false
+		}
+	}
+	ok, _ := block.Qualifiers.CheckGPA(student, limited.completed())
+	return ok
+}
+
+// Check reports the classes dropped for exceeding the block's limits, and a
+// block GPA that falls short. Grade qualifiers are copied onto the block's
+// rules when parsed, so they're reported per rule instead.
+func (block Block) Check(student *Student) []string {
+	limited, messages := block.limited(student)
+	if ok, message := block.Qualifiers.CheckGPA(student, limited.completed()); !ok {
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// limited drops the classes beyond the block's limits from its rules.
+func (block Block) limited(student *Student) (Block, []string) {
+	t := block.Qualifiers.tally()
+	rules := make([]Rule, len(block.Rules))
+	for i, rule := range block.Rules {
+		rules[i], _ = rule.limited(student, []*tally{t, rule.Qualifiers.tally()})
+	}
+	block.Rules = rules
+	return block, t.messages
+}
+
+func (block Block) completed() []string {
+	keys := make([]string, 0)
+	for _, rule := range block.Rules {
+		keys = append(keys, rule.completed()...)
+	}
+	return keys
+}
+
+// Flatten lists the requirement-holding rules of the block. See Rule.Flatten.
+func (block Block) Flatten(student *Student) []Rule {
+	limited, _ := block.limited(student)
+	rules := make([]Rule, 0)
+	for _, rule := range limited.Rules {
+		rules = append(rules, rule.Flatten(student)...)
+	}
+	return rules
 }
 
 type Student struct {
-	StudentID       string            `json:"studentID"`
-	Name            string            `json:"name"`
-	Email           string            `json:"email"`
-	GPA             float64           `json:"gpa"`
-	PercentComplete float64           `json:"percentComplete"`
-	CreditsApplied  float64           `json:"creditsApplied"`
-	Courses         map[string]Course `json:"courses"`
-	Taken           map[string]bool   `json:"taken"`
-	Blocks          []Block           `json:"blocks"`
-	Terms           []string          `json:"terms"`
+	StudentID       string              `json:"studentID"`
+	Name            string              `json:"name"`
+	Email           string              `json:"email"`
+	Audit           AuditInfo           `json:"audit"`
+	Degree          Degree              `json:"degree"`
+	Advisors        []Advisor           `json:"advisors"`
+	Notes           []Note              `json:"notes"`
+	Holds           []Hold              `json:"holds"`
+	GPA             float64             `json:"gpa"`
+	PercentComplete float64             `json:"percentComplete"`
+	CreditsApplied  float64             `json:"creditsApplied"`
+	Courses         map[string]Course   `json:"courses"`
+	Taken           map[string]bool     `json:"taken"`
+	Blocks          []Block             `json:"blocks"`
+	Terms           []string            `json:"terms"`
+	Goals           []Goal              `json:"goals"`
+	Transcript      Transcript          `json:"transcript"`
+	Exceptions      []Exception         `json:"exceptions"`
+	Graduation      *GraduationEstimate `json:"graduation,omitempty"`
+	Recommendations []Shortlist         `json:"recommendations,omitempty"`
 }
 
 func (student Student) ClassLevel() string {
@@ -395,7 +864,22 @@ func (student Student) Standing() string {
 	return ""
 }
 
+type SimilarCourse struct {
+	Key   string  `json:"key"`
+	Score float64 `json:"score"`
+}
+
 type Catalogue struct {
-	Courses map[string]Course `json:"courses"`
-	Terms   []string          `json:"terms"`
+	Courses map[string]Course          `json:"courses"`
+	Terms   []string                   `json:"terms"`
+	Similar map[string][]SimilarCourse `json:"similar,omitempty"`
+}
+
+func (catalogue Catalogue) Similarity(keyA string, keyB string) float64 {
+	for _, similar := range catalogue.Similar[keyA] {
+		if similar.Key == keyB {
+			return similar.Score
+		}
+	}
+	return 0.0
 }